@@ -19,6 +19,14 @@ type SQLDatabase interface {
 	Closer
 }
 
+// GormDatabase is SQLDatabase with gorm, Database returns the sqlx connection of the same pool
+type GormDatabase interface {
+	SQLDatabase
+
+	// Gorm returns the gorm connection
+	Gorm() *dbc.GormDB
+}
+
 type RedisDatabase interface {
 	Client() rdc.Rdc
 
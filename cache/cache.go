@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound returned by the loader when the data is not found, the result is cached with negative ttl
+var ErrNotFound = errors.New("cache: not found")
+
+const (
+	resultHit         = "hit"
+	resultMiss        = "miss"
+	resultNegativeHit = "negative_hit"
+	resultRefresh     = "refresh"
+	resultError       = "error"
+)
+
+// Cache is cache-aside on top of redis
+type Cache struct {
+	rdc   rdc.Rdc
+	opt   option
+	group singleflight.Group
+}
+
+// envelope is the cached value with the metadata of early refresh
+type envelope[T any] struct {
+	Value T `json:"v"`
+	// NotFound the loader returned not found
+	NotFound bool `json:"n,omitempty"`
+	// Delta duration of the loader in millisecond
+	Delta int64 `json:"d"`
+	// Expiry time in unix millisecond
+	Expiry int64 `json:"e"`
+}
+
+var defaultCache *Cache
+
+// New create new cache with redis client
+func New(client rdc.Rdc, opts ...OptionFunc) *Cache {
+	c := &Cache{rdc: client, opt: getDefaultOption()}
+	for _, opt := range opts {
+		opt(&c.opt)
+	}
+
+	return c
+}
+
+// SetDefault set the cache used by GetOrLoad
+func SetDefault(c *Cache) {
+	defaultCache = c
+}
+
+// GetOrLoad returns the value of the key from the default cache (SetDefault),
+// otherwise call the loader and cache the value with ttl, e.g.:
+//
+//	user, err := cache.GetOrLoad(ctx, "user:"+id, time.Hour, func(ctx context.Context) (User, error) {
+//		return repo.FindUser(ctx, id)
+//	})
+func GetOrLoad[T any](ctx context.Context, key string, ttl time.Duration, loader func(context.Context) (T, error)) (T, error) {
+	if defaultCache == nil {
+		var zero T
+		return zero, errors.New("cache: default cache not yet set. please set the default cache using, cache.SetDefault(cache.New(client))")
+	}
+
+	return GetOrLoadFrom(ctx, defaultCache, key, ttl, loader)
+}
+
+// GetOrLoadFrom returns the value of the key from c, otherwise call the loader and cache the value with ttl.
+// the concurrent calls of the same key in the process share a single loader call,
+// ErrNotFound is returned when the loader returned not found (see SetNotFound)
+func GetOrLoadFrom[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(context.Context) (T, error)) (value T, err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Cache:GetOrLoad")
+	defer func() {
+		if !errors.Is(err, ErrNotFound) {
+			trace.SetError(err)
+		}
+		trace.Finish()
+	}()
+
+	trace.Log("key", key)
+	trace.Log("ttl", ttl)
+
+	env, err := get[T](ctx, c, key)
+	switch {
+	case err == nil && env.NotFound:
+		trace.SetTag("result", resultNegativeHit)
+		monitoring.RecordCache(c.opt.name, resultNegativeHit)
+		return value, ErrNotFound
+	case err == nil:
+		trace.SetTag("result", resultHit)
+		monitoring.RecordCache(c.opt.name, resultHit)
+		if c.shouldRefresh(env.Delta, env.Expiry) {
+			monitoring.RecordCache(c.opt.name, resultRefresh)
+			go func() {
+				_, _, _ = c.group.Do(key, func() (interface{}, error) {
+					return load(context.WithoutCancel(ctx), c, key, ttl, loader, false)
+				})
+			}()
+		}
+		return env.Value, nil
+	case !errors.Is(err, redis.Nil):
+		// the redis is not available or the value is broken, load from the source
+		logger.Red(fmt.Sprintf("cache > failed to get %s: %s", key, err))
+		monitoring.RecordCache(c.opt.name, resultError)
+	default:
+		monitoring.RecordCache(c.opt.name, resultMiss)
+	}
+	trace.SetTag("result", resultMiss)
+
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		return load(context.WithoutCancel(ctx), c, key, ttl, loader, c.opt.lock)
+	})
+
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return value, res.Err
+		}
+
+		v, ok := res.Val.(T)
+		if !ok {
+			return value, fmt.Errorf("cache: type of %s is %T, not %T", key, res.Val, value)
+		}
+		return v, nil
+	}
+}
+
+// Invalidate delete the cached values
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	return c.rdc.Del(ctx, keys...)
+}
+
+// shouldRefresh returns true when the value should be refreshed before expired (XFetch),
+// the probability is grown near the expiration and with the longer loader
+func (c *Cache) shouldRefresh(delta, expiry int64) bool {
+	if c.opt.beta <= 0 || delta <= 0 {
+		return false
+	}
+
+	now := time.Now().UnixMilli()
+	return float64(now)-float64(delta)*c.opt.beta*math.Log(rand.Float64()) >= float64(expiry)
+}
+
+// get returns the cached envelope
+func get[T any](ctx context.Context, c *Cache, key string) (env envelope[T], err error) {
+	data, err := c.rdc.Get(ctx, key)
+	if err != nil {
+		return env, err
+	}
+
+	err = c.opt.serializer.Unmarshal([]byte(data), &env)
+	return env, err
+}
+
+// set cache the envelope
+func set[T any](ctx context.Context, c *Cache, key string, env envelope[T], ttl time.Duration) error {
+	data, err := c.opt.serializer.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	return c.rdc.Set(ctx, key, string(data), rdc.WithTTL(ttl))
+}
+
+// load call the loader and cache the result, with lock only one instance call the loader
+func load[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(context.Context) (T, error), lock bool) (interface{}, error) {
+	if lock {
+		unlock, env, found := waitOrLock[T](ctx, c, key)
+		if found {
+			if env.NotFound {
+				return nil, ErrNotFound
+			}
+			return env.Value, nil
+		}
+		defer unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opt.loadTimeout)
+	defer cancel()
+
+	start := time.Now()
+	value, err := loader(ctx)
+	delta := time.Since(start)
+
+	if err != nil {
+		if !c.opt.notFound(err) {
+			return nil, err
+		}
+
+		if c.opt.negativeTTL > 0 {
+			env := envelope[T]{NotFound: true, Delta: delta.Milliseconds(), Expiry: time.Now().Add(c.opt.negativeTTL).UnixMilli()}
+			if e := set(ctx, c, key, env, c.opt.negativeTTL); e != nil {
+				logger.Red(fmt.Sprintf("cache > failed to set %s: %s", key, e))
+			}
+		}
+
+		return nil, ErrNotFound
+	}
+
+	env := envelope[T]{Value: value, Delta: delta.Milliseconds(), Expiry: time.Now().Add(ttl).UnixMilli()}
+	if e := set(ctx, c, key, env, ttl); e != nil {
+		logger.Red(fmt.Sprintf("cache > failed to set %s: %s", key, e))
+	}
+
+	return value, nil
+}
+
+// waitOrLock acquire the lock of the key, returns the cached value when the value is set by the lock holder.
+// the lock is ignored when redis is not available or the wait exceeded, so the loader is still called.
+// the lock is already released when found is true, otherwise unlock must be called after the value is set
+func waitOrLock[T any](ctx context.Context, c *Cache, key string) (unlock func(), env envelope[T], found bool) {
+	lockKey := key + ":lock"
+	token := uuid.NewString()
+	unlock = func() {}
+
+	deadline := time.Now().Add(c.opt.lockWait)
+	for {
+		ok, err := c.rdc.SetNX(ctx, lockKey, token, c.opt.lockTTL)
+		if err != nil {
+			return unlock, env, false
+		}
+
+		if ok {
+			unlock = func() {
+				// only release the own lock, the lock may be expired and acquired by the other instance
+				if _, e := c.rdc.EvalScript(ctx, unlockScript, []string{lockKey}, token); e != nil {
+					logger.Red(fmt.Sprintf("cache > failed to unlock %s: %s", lockKey, e))
+				}
+			}
+
+			// the value may be set while waiting the lock, the lock is not needed anymore
+			if env, err = get[T](ctx, c, key); err == nil {
+				unlock()
+				return func() {}, env, true
+			}
+			return unlock, env, false
+		}
+
+		if time.Now().After(deadline) {
+			return unlock, env, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return unlock, env, false
+		case <-time.After(c.opt.lockPoll):
+		}
+
+		if env, err = get[T](ctx, c, key); err == nil {
+			return unlock, env, true
+		}
+	}
+}
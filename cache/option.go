@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+)
+
+type option struct {
+	name        string
+	serializer  Serializer
+	negativeTTL time.Duration
+	notFound    func(error) bool
+	beta        float64
+	lock        bool
+	lockTTL     time.Duration
+	lockWait    time.Duration
+	lockPoll    time.Duration
+	loadTimeout time.Duration
+}
+
+// OptionFunc option func for cache
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		name:        "default",
+		serializer:  JSON,
+		negativeTTL: env.GetDuration("CACHE_NEGATIVE_TTL", time.Minute),
+		notFound: func(err error) bool {
+			return errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows)
+		},
+		beta:        1,
+		lock:        env.GetBool("CACHE_LOCK_ON_MISS", false),
+		lockTTL:     env.GetDuration("CACHE_LOCK_TTL", 10*time.Second),
+		lockWait:    env.GetDuration("CACHE_LOCK_WAIT", 3*time.Second),
+		lockPoll:    50 * time.Millisecond,
+		loadTimeout: env.GetDuration("CACHE_LOAD_TIMEOUT", 10*time.Second),
+	}
+}
+
+// SetName set the name of the cache, used as label of the metrics
+func SetName(name string) OptionFunc {
+	return func(o *option) {
+		o.name = name
+	}
+}
+
+// SetSerializer set the serializer of the cached value, e.g.: JSON, Msgpack, Gob
+func SetSerializer(serializer Serializer) OptionFunc {
+	return func(o *option) {
+		o.serializer = serializer
+	}
+}
+
+// SetNegativeTTL set the ttl of not found result, 0 disable the negative caching
+func SetNegativeTTL(ttl time.Duration) OptionFunc {
+	return func(o *option) {
+		o.negativeTTL = ttl
+	}
+}
+
+// SetNotFound set the func to check the error of the loader is not found,
+// default is ErrNotFound and sql.ErrNoRows
+func SetNotFound(notFound func(error) bool) OptionFunc {
+	return func(o *option) {
+		o.notFound = notFound
+	}
+}
+
+// SetEarlyRefresh set the beta of the probabilistic early refresh, the value is refreshed in background
+// before expired with the probability grown near the expiration and the duration of the loader.
+// higher beta refresh earlier, 0 disable the early refresh (default is 1)
+func SetEarlyRefresh(beta float64) OptionFunc {
+	return func(o *option) {
+		o.beta = beta
+	}
+}
+
+// SetLockOnMiss lock the key across instances with redis when the value is missing,
+// only the lock holder call the loader and the others wait the value until lockWait.
+// lockTTL must be longer than the duration of the loader
+func SetLockOnMiss(lockTTL, lockWait time.Duration) OptionFunc {
+	return func(o *option) {
+		o.lock = true
+		o.lockTTL = lockTTL
+		o.lockWait = lockWait
+	}
+}
+
+// SetLoadTimeout set the timeout of the loader, the loader is shared by the concurrent callers
+// so it does not use the context of the caller
+func SetLoadTimeout(timeout time.Duration) OptionFunc {
+	return func(o *option) {
+		o.loadTimeout = timeout
+	}
+}
@@ -0,0 +1,12 @@
+package cache
+
+import "github.com/mqdvi-dp/go-common/config/database/rdc"
+
+// unlockScript delete the lock KEYS[1] only when the value is the token ARGV[1] of the lock holder,
+// so the lock expired and acquired by the other instance is not released. returns 1 when the lock is deleted
+var unlockScript = rdc.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Serializer encode and decode the cached value
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	// JSON serializer with encoding/json (default)
+	JSON Serializer = jsonSerializer{}
+	// Msgpack serializer with msgpack format, smaller and faster than JSON
+	Msgpack Serializer = msgpackSerializer{handle: new(codec.MsgpackHandle)}
+	// Gob serializer with encoding/gob, the unexported fields are not encoded
+	Gob Serializer = gobSerializer{}
+)
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type msgpackSerializer struct {
+	handle *codec.MsgpackHandle
+}
+
+func (m msgpackSerializer) Marshal(v interface{}) ([]byte, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, m.handle).Encode(v)
+	return b, err
+}
+
+func (m msgpackSerializer) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, m.handle).Decode(v)
+}
+
+type gobSerializer struct{}
+
+func (gobSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobSerializer) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,743 @@
+package dbc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Cond is a predicate of WHERE clause, the placeholder is "?" and rebind by SqlDbc before executed
+type Cond interface {
+	toSql() (string, []interface{}, error)
+}
+
+type expr struct {
+	sql  string
+	args []interface{}
+}
+
+func (e expr) toSql() (string, []interface{}, error) {
+	return e.sql, e.args, nil
+}
+
+// Expr raw predicate with "?" placeholder, e.g.: Expr("created_at > now() - ?::interval", "1 day")
+func Expr(sql string, args ...interface{}) Cond {
+	return expr{sql: sql, args: args}
+}
+
+// Eq column = value
+func Eq(column string, value interface{}) Cond {
+	return expr{sql: column + " = ?", args: []interface{}{value}}
+}
+
+// Neq column <> value
+func Neq(column string, value interface{}) Cond {
+	return expr{sql: column + " <> ?", args: []interface{}{value}}
+}
+
+// Gt column > value
+func Gt(column string, value interface{}) Cond {
+	return expr{sql: column + " > ?", args: []interface{}{value}}
+}
+
+// Gte column >= value
+func Gte(column string, value interface{}) Cond {
+	return expr{sql: column + " >= ?", args: []interface{}{value}}
+}
+
+// Lt column < value
+func Lt(column string, value interface{}) Cond {
+	return expr{sql: column + " < ?", args: []interface{}{value}}
+}
+
+// Lte column <= value
+func Lte(column string, value interface{}) Cond {
+	return expr{sql: column + " <= ?", args: []interface{}{value}}
+}
+
+// Like column LIKE value
+func Like(column string, value interface{}) Cond {
+	return expr{sql: column + " LIKE ?", args: []interface{}{value}}
+}
+
+// ILike column ILIKE value
+func ILike(column string, value interface{}) Cond {
+	return expr{sql: column + " ILIKE ?", args: []interface{}{value}}
+}
+
+// IsNull column IS NULL
+func IsNull(column string) Cond {
+	return expr{sql: column + " IS NULL"}
+}
+
+// IsNotNull column IS NOT NULL
+func IsNotNull(column string) Cond {
+	return expr{sql: column + " IS NOT NULL"}
+}
+
+type in struct {
+	column string
+	values interface{}
+	not    bool
+}
+
+// In column IN (values...), values must be a slice. empty slice never matches
+func In(column string, values interface{}) Cond {
+	return in{column: column, values: values}
+}
+
+// NotIn column NOT IN (values...), values must be a slice. empty slice always matches
+func NotIn(column string, values interface{}) Cond {
+	return in{column: column, values: values, not: true}
+}
+
+func (i in) toSql() (string, []interface{}, error) {
+	v := reflect.ValueOf(i.values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", nil, fmt.Errorf("value of IN %s must be a slice", i.column)
+	}
+
+	// []byte is a single value
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		return "", nil, fmt.Errorf("value of IN %s cannot be []byte", i.column)
+	}
+
+	if v.Len() == 0 {
+		if i.not {
+			return "1 = 1", nil, nil
+		}
+		return "1 = 0", nil, nil
+	}
+
+	args := make([]interface{}, 0, v.Len())
+	for idx := 0; idx < v.Len(); idx++ {
+		args = append(args, v.Index(idx).Interface())
+	}
+
+	operator := "IN"
+	if i.not {
+		operator = "NOT IN"
+	}
+
+	return fmt.Sprintf("%s %s (%s)", i.column, operator, placeholders(len(args))), args, nil
+}
+
+type junction struct {
+	operator string
+	conds    []Cond
+}
+
+// And join the predicates with AND
+func And(conds ...Cond) Cond {
+	return junction{operator: " AND ", conds: conds}
+}
+
+// Or join the predicates with OR
+func Or(conds ...Cond) Cond {
+	return junction{operator: " OR ", conds: conds}
+}
+
+func (j junction) toSql() (string, []interface{}, error) {
+	if len(j.conds) == 0 {
+		return "", nil, nil
+	}
+
+	var parts []string
+	var args []interface{}
+	for _, c := range j.conds {
+		s, a, err := c.toSql()
+		if err != nil {
+			return "", nil, err
+		}
+		if s == "" {
+			continue
+		}
+
+		parts = append(parts, s)
+		args = append(args, a...)
+	}
+
+	if len(parts) == 1 {
+		return parts[0], args, nil
+	}
+
+	return "(" + strings.Join(parts, j.operator) + ")", args, nil
+}
+
+// where clause shared by select, update and delete
+type where struct {
+	conds []Cond
+}
+
+func (w *where) add(cond Cond) {
+	w.conds = append(w.conds, cond)
+}
+
+// SelectBuilder builder of SELECT query
+type SelectBuilder struct {
+	columns []string
+	from    string
+	joins   []expr
+	where   where
+	groupBy []string
+	having  []Cond
+	orderBy []string
+	limit   *int64
+	offset  *int64
+}
+
+// Select create SELECT query builder, e.g.:
+//
+//	dbc.Select("id", "name").From("users").
+//		Where(dbc.Eq("status", status)).
+//		WhereIf(keyword != "", dbc.ILike("name", "%"+keyword+"%")).
+//		OrderBy("id DESC").Limit(10).
+//		Select(ctx, db, &users)
+func Select(columns ...string) *SelectBuilder {
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+
+	return &SelectBuilder{columns: columns}
+}
+
+// From set the table
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+	return b
+}
+
+// Join add join clause, e.g.: Join("LEFT JOIN roles r ON r.id = u.role_id")
+func (b *SelectBuilder) Join(join string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, expr{sql: join, args: args})
+	return b
+}
+
+// Where add predicate, multiple predicates joined with AND
+func (b *SelectBuilder) Where(cond Cond) *SelectBuilder {
+	b.where.add(cond)
+	return b
+}
+
+// WhereIf add predicate only when ok is true
+func (b *SelectBuilder) WhereIf(ok bool, cond Cond) *SelectBuilder {
+	if ok {
+		b.where.add(cond)
+	}
+	return b
+}
+
+// GroupBy set group by columns
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// Having add predicate of group by
+func (b *SelectBuilder) Having(cond Cond) *SelectBuilder {
+	b.having = append(b.having, cond)
+	return b
+}
+
+// OrderBy add order by, e.g.: OrderBy("created_at DESC", "id")
+func (b *SelectBuilder) OrderBy(orders ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, orders...)
+	return b
+}
+
+// Limit set limit
+func (b *SelectBuilder) Limit(limit int64) *SelectBuilder {
+	b.limit = &limit
+	return b
+}
+
+// Offset set offset
+func (b *SelectBuilder) Offset(offset int64) *SelectBuilder {
+	b.offset = &offset
+	return b
+}
+
+// ToSql returns the query with "?" placeholder and the arguments
+func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
+	if b.from == "" {
+		return "", nil, errors.New("select query must have table")
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(b.columns, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.from)
+
+	for _, j := range b.joins {
+		sb.WriteString(" ")
+		sb.WriteString(j.sql)
+		args = append(args, j.args...)
+	}
+
+	if _, err := writeWhere(&sb, &args, b.where.conds); err != nil {
+		return "", nil, err
+	}
+
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(b.groupBy, ", "))
+	}
+
+	if len(b.having) > 0 {
+		s, a, err := And(b.having...).toSql()
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(" HAVING ")
+		sb.WriteString(s)
+		args = append(args, a...)
+	}
+
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+
+	if b.limit != nil {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", *b.limit))
+	}
+
+	if b.offset != nil {
+		sb.WriteString(fmt.Sprintf(" OFFSET %d", *b.offset))
+	}
+
+	return sb.String(), args, nil
+}
+
+// Select execute the query and scan all rows into dest
+func (b *SelectBuilder) Select(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runSelect(ctx, db, b, dest)
+}
+
+// Get execute the query and scan a single row into dest
+func (b *SelectBuilder) Get(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runGet(ctx, db, b, dest)
+}
+
+// InsertBuilder builder of INSERT query
+type InsertBuilder struct {
+	table     string
+	columns   []string
+	rows      [][]interface{}
+	conflict  []string
+	doNothing bool
+	doUpdate  []string
+	returning []string
+	err       error
+}
+
+// Insert create INSERT query builder, e.g.:
+//
+//	dbc.Insert("users").Columns("id", "name").Values(1, "foo").
+//		OnConflict("id").DoUpdateSet("name").
+//		Returning("id").Get(ctx, db, &id)
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Columns set the columns
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values add a row, call multiple times for bulk insert
+func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	if len(values) != len(b.columns) && b.err == nil {
+		b.err = fmt.Errorf("insert into %s: %d values for %d columns", b.table, len(values), len(b.columns))
+	}
+
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// SetMap set the columns and a row from map
+func (b *InsertBuilder) SetMap(values map[string]interface{}) *InsertBuilder {
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	// keep the query stable
+	sort.Strings(columns)
+
+	row := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		row = append(row, values[column])
+	}
+
+	b.columns = columns
+	b.rows = [][]interface{}{row}
+	return b
+}
+
+// OnConflict set the conflict target of upsert, e.g.: OnConflict("id")
+func (b *InsertBuilder) OnConflict(columns ...string) *InsertBuilder {
+	b.conflict = columns
+	return b
+}
+
+// DoNothing ignore the conflicted row
+func (b *InsertBuilder) DoNothing() *InsertBuilder {
+	b.doNothing = true
+	return b
+}
+
+// DoUpdateSet update the columns of the conflicted row with the inserted value (EXCLUDED)
+func (b *InsertBuilder) DoUpdateSet(columns ...string) *InsertBuilder {
+	b.doUpdate = columns
+	return b
+}
+
+// Returning set the returning columns
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	b.returning = columns
+	return b
+}
+
+// ToSql returns the query with "?" placeholder and the arguments
+func (b *InsertBuilder) ToSql() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.columns) == 0 || len(b.rows) == 0 {
+		return "", nil, fmt.Errorf("insert into %s must have columns and values", b.table)
+	}
+	if len(b.conflict) > 0 && !b.doNothing && len(b.doUpdate) == 0 {
+		return "", nil, fmt.Errorf("insert into %s: on conflict must have DoNothing or DoUpdateSet", b.table)
+	}
+	if len(b.doUpdate) > 0 && len(b.conflict) == 0 {
+		return "", nil, fmt.Errorf("insert into %s: DoUpdateSet must have OnConflict target", b.table)
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString(fmt.Sprintf("INSERT INTO %s (%s) VALUES ", b.table, strings.Join(b.columns, ", ")))
+	for i, row := range b.rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(" + placeholders(len(row)) + ")")
+		args = append(args, row...)
+	}
+
+	if b.doNothing || len(b.doUpdate) > 0 {
+		sb.WriteString(" ON CONFLICT")
+		if len(b.conflict) > 0 {
+			sb.WriteString(" (" + strings.Join(b.conflict, ", ") + ")")
+		}
+
+		if len(b.doUpdate) > 0 {
+			sets := make([]string, 0, len(b.doUpdate))
+			for _, column := range b.doUpdate {
+				sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+			}
+			sb.WriteString(" DO UPDATE SET " + strings.Join(sets, ", "))
+		} else {
+			sb.WriteString(" DO NOTHING")
+		}
+	}
+
+	writeReturning(&sb, b.returning)
+	return sb.String(), args, nil
+}
+
+// Exec execute the query
+func (b *InsertBuilder) Exec(ctx context.Context, db SqlDbc) (sql.Result, error) {
+	return runExec(ctx, db, b)
+}
+
+// Get execute the query and scan the returning columns into dest, always executed on master
+func (b *InsertBuilder) Get(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runGet(ctx, writer(ctx, db), b, dest)
+}
+
+// Select execute the query and scan the returning columns of all rows into dest, always executed on master
+func (b *InsertBuilder) Select(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runSelect(ctx, writer(ctx, db), b, dest)
+}
+
+// UpdateBuilder builder of UPDATE query
+type UpdateBuilder struct {
+	table     string
+	sets      []expr
+	where     where
+	all       bool
+	returning []string
+}
+
+// Update create UPDATE query builder, e.g.:
+//
+//	dbc.Update("users").Set("name", name).SetIf(email != "", "email", email).
+//		Where(dbc.Eq("id", id)).Exec(ctx, db)
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set column = value
+func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
+	b.sets = append(b.sets, expr{sql: column + " = ?", args: []interface{}{value}})
+	return b
+}
+
+// SetIf set column = value only when ok is true
+func (b *UpdateBuilder) SetIf(ok bool, column string, value interface{}) *UpdateBuilder {
+	if ok {
+		b.Set(column, value)
+	}
+	return b
+}
+
+// SetExpr set column with expression, e.g.: SetExpr("balance", "balance + ?", amount)
+func (b *UpdateBuilder) SetExpr(column, expression string, args ...interface{}) *UpdateBuilder {
+	b.sets = append(b.sets, expr{sql: column + " = " + expression, args: args})
+	return b
+}
+
+// Where add predicate, multiple predicates joined with AND
+func (b *UpdateBuilder) Where(cond Cond) *UpdateBuilder {
+	b.where.add(cond)
+	return b
+}
+
+// WhereIf add predicate only when ok is true
+func (b *UpdateBuilder) WhereIf(ok bool, cond Cond) *UpdateBuilder {
+	if ok {
+		b.where.add(cond)
+	}
+	return b
+}
+
+// All allow the update without predicate, otherwise ToSql returns an error to prevent updating every row
+// (e.g.: when all predicates of WhereIf are skipped)
+func (b *UpdateBuilder) All() *UpdateBuilder {
+	b.all = true
+	return b
+}
+
+// Returning set the returning columns
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	b.returning = columns
+	return b
+}
+
+// ToSql returns the query with "?" placeholder and the arguments
+func (b *UpdateBuilder) ToSql() (string, []interface{}, error) {
+	if len(b.sets) == 0 {
+		return "", nil, fmt.Errorf("update %s must have set values", b.table)
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+
+	sets := make([]string, 0, len(b.sets))
+	for _, s := range b.sets {
+		sets = append(sets, s.sql)
+		args = append(args, s.args...)
+	}
+	sb.WriteString(fmt.Sprintf("UPDATE %s SET %s", b.table, strings.Join(sets, ", ")))
+
+	ok, err := writeWhere(&sb, &args, b.where.conds)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok && !b.all {
+		return "", nil, fmt.Errorf("update %s must have predicate, use All to update every row", b.table)
+	}
+
+	writeReturning(&sb, b.returning)
+	return sb.String(), args, nil
+}
+
+// Exec execute the query
+func (b *UpdateBuilder) Exec(ctx context.Context, db SqlDbc) (sql.Result, error) {
+	return runExec(ctx, db, b)
+}
+
+// Get execute the query and scan the returning columns into dest, always executed on master
+func (b *UpdateBuilder) Get(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runGet(ctx, writer(ctx, db), b, dest)
+}
+
+// Select execute the query and scan the returning columns of all rows into dest, always executed on master
+func (b *UpdateBuilder) Select(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runSelect(ctx, writer(ctx, db), b, dest)
+}
+
+// DeleteBuilder builder of DELETE query
+type DeleteBuilder struct {
+	table     string
+	where     where
+	all       bool
+	returning []string
+}
+
+// Delete create DELETE query builder, e.g.:
+//
+//	dbc.Delete("sessions").Where(dbc.Lt("expired_at", now)).Exec(ctx, db)
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Where add predicate, multiple predicates joined with AND
+func (b *DeleteBuilder) Where(cond Cond) *DeleteBuilder {
+	b.where.add(cond)
+	return b
+}
+
+// WhereIf add predicate only when ok is true
+func (b *DeleteBuilder) WhereIf(ok bool, cond Cond) *DeleteBuilder {
+	if ok {
+		b.where.add(cond)
+	}
+	return b
+}
+
+// All allow the delete without predicate, otherwise ToSql returns an error to prevent deleting every row
+// (e.g.: when all predicates of WhereIf are skipped)
+func (b *DeleteBuilder) All() *DeleteBuilder {
+	b.all = true
+	return b
+}
+
+// Returning set the returning columns
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	b.returning = columns
+	return b
+}
+
+// ToSql returns the query with "?" placeholder and the arguments
+func (b *DeleteBuilder) ToSql() (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+
+	sb.WriteString("DELETE FROM " + b.table)
+	ok, err := writeWhere(&sb, &args, b.where.conds)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok && !b.all {
+		return "", nil, fmt.Errorf("delete from %s must have predicate, use All to delete every row", b.table)
+	}
+
+	writeReturning(&sb, b.returning)
+	return sb.String(), args, nil
+}
+
+// Exec execute the query
+func (b *DeleteBuilder) Exec(ctx context.Context, db SqlDbc) (sql.Result, error) {
+	return runExec(ctx, db, b)
+}
+
+// Get execute the query and scan the returning columns into dest, always executed on master
+func (b *DeleteBuilder) Get(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runGet(ctx, writer(ctx, db), b, dest)
+}
+
+// Select execute the query and scan the returning columns of all rows into dest, always executed on master
+func (b *DeleteBuilder) Select(ctx context.Context, db SqlDbc, dest interface{}) error {
+	return runSelect(ctx, writer(ctx, db), b, dest)
+}
+
+// builder is implemented by all query builders
+type builder interface {
+	ToSql() (string, []interface{}, error)
+}
+
+// build returns the query rebind by the database
+func build(db SqlDbc, b builder) (string, []interface{}, error) {
+	query, args, err := b.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return db.Rebind(query), args, nil
+}
+
+// writer returns the connection of the write with RETURNING, the Router routes Get and Select to the replicas,
+// so the write is executed on master and remembered for the read-your-writes
+func writer(ctx context.Context, db SqlDbc) SqlDbc {
+	r, ok := db.(*Router)
+	if !ok {
+		return db
+	}
+
+	r.wrote(ctx)
+	return r.Master()
+}
+
+func runSelect(ctx context.Context, db SqlDbc, b builder, dest interface{}) error {
+	query, args, err := build(db, b)
+	if err != nil {
+		return err
+	}
+
+	return db.Select(ctx, dest, query, args...)
+}
+
+func runGet(ctx context.Context, db SqlDbc, b builder, dest interface{}) error {
+	query, args, err := build(db, b)
+	if err != nil {
+		return err
+	}
+
+	return db.Get(ctx, dest, query, args...)
+}
+
+func runExec(ctx context.Context, db SqlDbc, b builder) (sql.Result, error) {
+	query, args, err := build(db, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Exec(ctx, query, args...)
+}
+
+// writeWhere write the predicates joined with AND, returns false when there is no predicate
+func writeWhere(sb *strings.Builder, args *[]interface{}, conds []Cond) (bool, error) {
+	var parts []string
+	for _, c := range conds {
+		s, a, err := c.toSql()
+		if err != nil {
+			return false, err
+		}
+		if s == "" {
+			continue
+		}
+
+		parts = append(parts, s)
+		*args = append(*args, a...)
+	}
+
+	if len(parts) == 0 {
+		return false, nil
+	}
+
+	sb.WriteString(" WHERE ")
+	sb.WriteString(strings.Join(parts, " AND "))
+	return true, nil
+}
+
+func writeReturning(sb *strings.Builder, returning []string) {
+	if len(returning) > 0 {
+		sb.WriteString(" RETURNING ")
+		sb.WriteString(strings.Join(returning, ", "))
+	}
+}
+
+// placeholders returns n placeholders, e.g.: ?, ?, ?
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
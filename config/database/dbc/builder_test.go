@@ -0,0 +1,179 @@
+package dbc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderToSql(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder builder
+		query   string
+		args    []interface{}
+		wantErr bool
+	}{
+		{
+			name:    "select all columns",
+			builder: Select().From("users"),
+			query:   "SELECT * FROM users",
+		},
+		{
+			name: "select with where, order, limit and offset",
+			builder: Select("id", "name").From("users").
+				Where(Eq("status", "active")).
+				WhereIf(false, Eq("role", "admin")).
+				WhereIf(true, ILike("name", "%foo%")).
+				OrderBy("id DESC").Limit(10).Offset(20),
+			query: "SELECT id, name FROM users WHERE status = ? AND name ILIKE ? ORDER BY id DESC LIMIT 10 OFFSET 20",
+			args:  []interface{}{"active", "%foo%"},
+		},
+		{
+			name: "select with join, group by and having",
+			builder: Select("r.name", "COUNT(*)").From("users u").
+				Join("LEFT JOIN roles r ON r.id = u.role_id AND r.tenant = ?", "t1").
+				Where(IsNull("u.deleted_at")).
+				GroupBy("r.name").
+				Having(Gt("COUNT(*)", 1)),
+			query: "SELECT r.name, COUNT(*) FROM users u LEFT JOIN roles r ON r.id = u.role_id AND r.tenant = ? " +
+				"WHERE u.deleted_at IS NULL GROUP BY r.name HAVING COUNT(*) > ?",
+			args: []interface{}{"t1", 1},
+		},
+		{
+			name:    "select with or and in",
+			builder: Select("id").From("users").Where(Or(In("id", []int{1, 2}), And(Neq("status", "x"), Lte("age", 30)))),
+			query:   "SELECT id FROM users WHERE (id IN (?, ?) OR (status <> ? AND age <= ?))",
+			args:    []interface{}{1, 2, "x", 30},
+		},
+		{
+			name:    "select with empty in never matches",
+			builder: Select("id").From("users").Where(In("id", []int{})).Where(NotIn("id", []string{})),
+			query:   "SELECT id FROM users WHERE 1 = 0 AND 1 = 1",
+		},
+		{
+			name:    "select in must be a slice",
+			builder: Select("id").From("users").Where(In("id", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "select in cannot be bytes",
+			builder: Select("id").From("users").Where(In("id", []byte("ab"))),
+			wantErr: true,
+		},
+		{
+			name:    "select without table",
+			builder: Select("id"),
+			wantErr: true,
+		},
+		{
+			name:    "insert bulk with returning",
+			builder: Insert("users").Columns("id", "name").Values(1, "foo").Values(2, "bar").Returning("id"),
+			query:   "INSERT INTO users (id, name) VALUES (?, ?), (?, ?) RETURNING id",
+			args:    []interface{}{1, "foo", 2, "bar"},
+		},
+		{
+			name:    "insert from map is sorted by column",
+			builder: Insert("users").SetMap(map[string]interface{}{"name": "foo", "id": 1, "age": 20}),
+			query:   "INSERT INTO users (age, id, name) VALUES (?, ?, ?)",
+			args:    []interface{}{20, 1, "foo"},
+		},
+		{
+			name:    "insert on conflict do update",
+			builder: Insert("users").Columns("id", "name").Values(1, "foo").OnConflict("id").DoUpdateSet("name"),
+			query:   "INSERT INTO users (id, name) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name",
+			args:    []interface{}{1, "foo"},
+		},
+		{
+			name:    "insert on conflict do nothing",
+			builder: Insert("users").Columns("id").Values(1).OnConflict("id").DoNothing(),
+			query:   "INSERT INTO users (id) VALUES (?) ON CONFLICT (id) DO NOTHING",
+			args:    []interface{}{1},
+		},
+		{
+			name:    "insert do nothing without conflict target",
+			builder: Insert("users").Columns("id").Values(1).DoNothing(),
+			query:   "INSERT INTO users (id) VALUES (?) ON CONFLICT DO NOTHING",
+			args:    []interface{}{1},
+		},
+		{
+			name:    "insert on conflict without action",
+			builder: Insert("users").Columns("id").Values(1).OnConflict("id"),
+			wantErr: true,
+		},
+		{
+			name:    "insert do update without conflict target",
+			builder: Insert("users").Columns("id", "name").Values(1, "foo").DoUpdateSet("name"),
+			wantErr: true,
+		},
+		{
+			name:    "insert values mismatch the columns",
+			builder: Insert("users").Columns("id", "name").Values(1),
+			wantErr: true,
+		},
+		{
+			name:    "insert without values",
+			builder: Insert("users").Columns("id"),
+			wantErr: true,
+		},
+		{
+			name: "update with set expression and returning",
+			builder: Update("wallets").Set("updated_by", "foo").SetIf(false, "note", "bar").
+				SetExpr("balance", "balance + ?", 10).
+				Where(Eq("id", 1)).Returning("balance"),
+			query: "UPDATE wallets SET updated_by = ?, balance = balance + ? WHERE id = ? RETURNING balance",
+			args:  []interface{}{"foo", 10, 1},
+		},
+		{
+			name:    "update without set",
+			builder: Update("users").Where(Eq("id", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "update without predicate",
+			builder: Update("users").Set("name", "foo").WhereIf(false, Eq("id", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "update with empty and predicate",
+			builder: Update("users").Set("name", "foo").Where(And()),
+			wantErr: true,
+		},
+		{
+			name:    "update all rows",
+			builder: Update("users").Set("active", false).All(),
+			query:   "UPDATE users SET active = ?",
+			args:    []interface{}{false},
+		},
+		{
+			name:    "delete with returning",
+			builder: Delete("sessions").Where(Lt("expired_at", 100)).Returning("id"),
+			query:   "DELETE FROM sessions WHERE expired_at < ? RETURNING id",
+			args:    []interface{}{100},
+		},
+		{
+			name:    "delete without predicate",
+			builder: Delete("sessions").WhereIf(false, Eq("id", 1)),
+			wantErr: true,
+		},
+		{
+			name:    "delete all rows",
+			builder: Delete("sessions").All(),
+			query:   "DELETE FROM sessions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.builder.ToSql()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.query, query)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/mqdvi-dp/go-common/logger"
@@ -13,16 +14,18 @@ import (
 func (d *DB) Queryx(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:Queryx")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	rows, err = d.DB.QueryxContext(ctx, query, args...)
 	return
@@ -31,15 +34,17 @@ func (d *DB) Queryx(ctx context.Context, query string, args ...interface{}) (row
 func (d *DB) QueryRowx(ctx context.Context, query string, args ...interface{}) (row *sqlx.Row) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:QueryRowx")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, row.Err())
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	row = d.DB.QueryRowxContext(ctx, query, args...)
 	return
@@ -48,15 +53,17 @@ func (d *DB) QueryRowx(ctx context.Context, query string, args ...interface{}) (
 func (d *DB) NamedExec(ctx context.Context, query string, args interface{}) (row sql.Result, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:NamedExec")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, nil, query, nil, start, err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args)
+	log = logger.DBContext(ctx, logger.Sql, query, args)
 
 	// log tracer
 	trace.Log("exec", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 	res, err := d.DB.NamedExecContext(ctx, query, args)
 	if err != nil {
 		trace.SetError(err)
@@ -68,15 +75,17 @@ func (d *DB) NamedExec(ctx context.Context, query string, args interface{}) (row
 func (d *DB) Exec(ctx context.Context, query string, args ...interface{}) (row sql.Result, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:Exec")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("exec", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 	res, err := d.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		trace.SetError(err)
@@ -88,16 +97,18 @@ func (d *DB) Exec(ctx context.Context, query string, args ...interface{}) (row s
 func (d *DB) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:Get")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	err = d.DB.GetContext(ctx, dest, query, args...)
 	return
@@ -106,16 +117,18 @@ func (d *DB) Get(ctx context.Context, dest interface{}, query string, args ...in
 func (d *DB) GetWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:GetWithIn")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create parameters "in"
 	query, args, err = sqlx.In(query, args...)
@@ -134,16 +147,18 @@ func (d *DB) GetWithIn(ctx context.Context, dest interface{}, query string, args
 func (d *DB) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:Select")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	err = d.DB.SelectContext(ctx, dest, query, args...)
 	return
@@ -152,16 +167,18 @@ func (d *DB) Select(ctx context.Context, dest interface{}, query string, args ..
 func (d *DB) SelectWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:SelectWithIn")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create parameters "in"
 	query, args, err = sqlx.In(query, args...)
@@ -180,16 +197,18 @@ func (d *DB) SelectWithIn(ctx context.Context, dest interface{}, query string, a
 func (d *DB) Preparex(ctx context.Context, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:Preparex")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.Monitor.observe(ctx, d.DB, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create statement query
 	stmt, err := d.DB.PreparexContext(ctx, query)
@@ -228,7 +247,7 @@ func (d *DB) Rebind(query string) string {
 func (d *DB) MustBegin() *Tx {
 	tx := d.DB.MustBegin()
 
-	return &Tx{tx}
+	return &Tx{DB: tx, monitor: d.Monitor}
 }
 
 func (d *DB) Close() error {
@@ -239,34 +258,86 @@ func (d *DB) Ping() error {
 	return d.DB.Ping()
 }
 
-func (d *DB) StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error) error {
-	var err error
-	var tx *Tx
+// StartTransaction starts a transaction, commit when txFunc returns nil, otherwise rollback.
+// when the context already carries a transaction (e.g.: called inside txFunc), it joins the transaction
+// with SAVEPOINT like Tx.StartTransaction instead of starting a new transaction
+func (d *DB) StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error, opts ...TxOptionFunc) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok {
+		return tx.StartTransaction(ctx, txFunc, opts...)
+	}
+
+	opt := getTxOption(opts...)
 
 	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:StartTransaction")
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%s", r)
+	defer trace.Finish()
+
+	trace.Log("isolation", opt.isolation.String())
+	trace.Log("read_only", opt.readOnly)
+	trace.Log("max_attempts", opt.maxAttempts)
+
+	backoff := opt.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err = d.runTransaction(ctx, txFunc, opt, attempt)
+		trace.SetTag("attempts", attempt)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= opt.maxAttempts || !IsRetryable(err) {
 			trace.SetError(err)
+			return err
+		}
 
-			if tx != nil {
-				_ = tx.Rollback()
-			}
+		// wait before replay the transaction
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			trace.SetError(ctx.Err())
+			return err
+		}
+		backoff *= 2
+	}
+}
 
-			return
-		} else if err != nil {
-			_ = tx.Rollback()
+// runTransaction run txFunc in a fresh transaction, commit when txFunc returns nil, otherwise rollback
+func (d *DB) runTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error, opt txOption, attempt int) (err error) {
+	// record every attempt of the transaction
+	log := logger.DBContext(ctx, logger.SqlTx, "TRANSACTION", fmt.Sprintf("attempt: %d", attempt), fmt.Sprintf("isolation: %s", opt.isolation))
+	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("Sql:Transaction:Attempt%d", attempt))
+	defer func() {
+		if err != nil {
 			trace.SetError(err)
-			return
+			log.Arguments = append(log.Arguments, fmt.Sprintf("error: %s", err))
+		} else {
+			log.Arguments = append(log.Arguments, "committed")
 		}
 
-		_ = tx.Commit()
+		log.Store(ctx)
 		trace.Finish()
 	}()
 
-	tx = d.MustBegin()
+	trace.SetTag("attempt", attempt)
+
+	sqlTx, err := d.DB.BeginTxx(ctx, opt.txOptions())
+	if err != nil {
+		return err
+	}
+	tx := &Tx{DB: sqlTx, monitor: d.Monitor}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s", r)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		err = tx.Commit()
+	}()
 
-	err = txFunc(ctx, tx)
+	err = txFunc(withTx(ctx, tx), tx)
 	return err
 }
 
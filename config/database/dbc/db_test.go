@@ -0,0 +1,44 @@
+package dbc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBStartTransactionNested(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	db := &DB{DB: sqlx.NewDb(conn, "sqlmock")}
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = db.StartTransaction(ctx, func(ctx context.Context, tx SqlDbc) error {
+		// the nested call on DB joins the transaction of the context
+		assert.NoError(t, db.StartTransaction(ctx, func(ctx context.Context, nested SqlDbc) error {
+			assert.Equal(t, 1, nested.(*Tx).savepoint)
+			return nil
+		}))
+
+		// the failed nested transaction only rollback to the savepoint
+		assert.Error(t, db.StartTransaction(ctx, func(ctx context.Context, _ SqlDbc) error {
+			return errors.New("failed")
+		}))
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,21 +11,31 @@ import (
 // GormDB is an instance with field struct non-transaction connection with gorm.io v2
 type GormDB struct {
 	DB *gorm.DB
+	// Sqlx is the sqlx connection of the same pool, used by the transaction
+	Sqlx *DB
 }
 
 // GormTx is an instance with field struct transaction connection with gorm.io v2
 type GormTx struct {
-	DB *gorm.Tx
+	DB *gorm.DB
+	// Sqlx is the same transaction for sqlx
+	Sqlx SqlDbc
 }
 
 // DB is an instance with field struct non-transactional connection
 type DB struct {
 	DB *sqlx.DB
+	// Monitor log the slow queries, the query metrics are recorded even when it is nil
+	Monitor *Monitor
 }
 
 // Tx is istance with field struct transactional connection
 type Tx struct {
 	DB *sqlx.Tx
+	// savepoint is the nested level of the transaction, 0 is the root transaction
+	savepoint int
+	// monitor inherited from the DB
+	monitor *Monitor
 }
 
 type SqlDbc interface {
@@ -42,12 +52,16 @@ type SqlDbc interface {
 	Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 
 	// GetWithIn return a single row data by conditional IN
+	//
+	// Deprecated: use the query builder with dbc.In, e.g.: dbc.Select().From(table).Where(dbc.In(column, values)).Get(ctx, db, dest)
 	GetWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 
 	// Select returns a lot of data
 	Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 
 	// SelectWithIn retruns a lot data with query `in`
+	//
+	// Deprecated: use the query builder with dbc.In, e.g.: dbc.Select().From(table).Where(dbc.In(column, values)).Select(ctx, db, dest)
 	SelectWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 
 	// Rebind query sqlx.Name into paramater
@@ -68,8 +82,10 @@ type SqlDbc interface {
 	// Check connection
 	Ping() error
 
-	// StartTransaction starts a transaction query
-	StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error) error
+	// StartTransaction starts a transaction query, commit when txFunc returns nil, otherwise rollback.
+	// calling StartTransaction inside a transaction starts a nested transaction with SAVEPOINT,
+	// the options only apply to the root transaction, the retry (TxOptionRetry) replay txFunc with a fresh transaction
+	StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error, opts ...TxOptionFunc) error
 
 	// Transaction if you want support transactional connection
 	Transaction
@@ -1,7 +1,34 @@
 package dbc
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
 
 var (
 	ErrNoRowsAffected = errors.New("no rows affected")
 )
+
+// retryable postgres error codes of the transaction
+var retryableCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// IsRetryable returns true when the error is serialization failure or deadlock detected,
+// the transaction can be replayed with a fresh transaction. both lib/pq (DriverPostgres) and pgx (DriverPgx) errors are checked
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return retryableCodes[string(pqErr.Code)]
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return retryableCodes[pgErr.Code]
+	}
+
+	return false
+}
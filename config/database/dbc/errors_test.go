@@ -0,0 +1,34 @@
+package dbc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "other error", err: errors.New("foo"), want: false},
+		{name: "pq serialization failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "pq deadlock detected", err: fmt.Errorf("update: %w", &pq.Error{Code: "40P01"}), want: true},
+		{name: "pq unique violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "pgx serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "pgx deadlock detected", err: fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "pgx unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
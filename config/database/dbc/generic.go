@@ -0,0 +1,160 @@
+package dbc
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/mqdvi-dp/go-common/errs"
+	"github.com/mqdvi-dp/go-common/types"
+)
+
+const defaultLimit = 10
+
+// columnPattern allowed column name of keyset pagination
+var columnPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// KeysetPage parameter of keyset (cursor) pagination
+type KeysetPage struct {
+	// Column unique and sortable column selected by the query (without table prefix), e.g.: id
+	Column string
+	// Cursor from the previous page (types.CursorMeta NextCursor), empty for the first page
+	Cursor string
+	Limit  int64
+	Desc   bool
+}
+
+// SelectAll returns all rows of the query, returns empty slice when there is no row
+func SelectAll[T any](ctx context.Context, db SqlDbc, query string, args ...interface{}) ([]T, error) {
+	result := make([]T, 0)
+	if err := db.Select(ctx, &result, query, args...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetOne returns a single row of the query, sql.ErrNoRows is mapped into errs.SQL_ERROR_NO_ROWS
+func GetOne[T any](ctx context.Context, db SqlDbc, query string, args ...interface{}) (T, error) {
+	var result T
+	if err := db.Get(ctx, &result, query, args...); err != nil {
+		return result, noRows(err)
+	}
+
+	return result, nil
+}
+
+// Paginate returns rows of the page and the metadata, the total data is counted from the query.
+// the query must not contain LIMIT and OFFSET, page start from 1
+func Paginate[T any](ctx context.Context, db SqlDbc, query string, page, limit int64, args ...interface{}) ([]T, *types.Meta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+
+	var total int64
+	if err := db.Get(ctx, &total, fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS paginate", query), args...); err != nil {
+		return nil, nil, err
+	}
+
+	result, err := SelectAll[T](ctx, db, fmt.Sprintf("%s LIMIT %d OFFSET %d", query, limit, (page-1)*limit), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, types.CreateMetaData(page, limit, total), nil
+}
+
+// SelectKeyset returns rows after the cursor ordered by the column, suitable for large tables.
+// the query must not contain ORDER BY and LIMIT, and uses postgres placeholder ($1, $2, ...).
+// cursorOf returns the column value of the row, used for the next cursor
+func SelectKeyset[T any](ctx context.Context, db SqlDbc, query string, page KeysetPage, cursorOf func(T) interface{}, args ...interface{}) ([]T, *types.CursorMeta, error) {
+	if !columnPattern.MatchString(page.Column) {
+		return nil, nil, fmt.Errorf("invalid keyset column %q", page.Column)
+	}
+	if page.Limit < 1 {
+		page.Limit = defaultLimit
+	}
+
+	operator, order := ">", "ASC"
+	if page.Desc {
+		operator, order = "<", "DESC"
+	}
+
+	where := ""
+	if page.Cursor != "" {
+		value, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		args = append(args, value)
+		where = fmt.Sprintf(" WHERE %s %s $%d", page.Column, operator, len(args))
+	}
+
+	// fetch one more row to know whether the next page exists
+	q := fmt.Sprintf("SELECT * FROM (%s) AS keyset%s ORDER BY %s %s LIMIT %d", query, where, page.Column, order, page.Limit+1)
+	result, err := SelectAll[T](ctx, db, q, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &types.CursorMeta{PerPage: page.Limit}
+	if int64(len(result)) > page.Limit {
+		result = result[:page.Limit]
+		meta.HasNext = true
+		meta.NextCursor, err = encodeCursor(cursorOf(result[len(result)-1]))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return result, meta, nil
+}
+
+// noRows map sql.ErrNoRows into errs.SQL_ERROR_NO_ROWS
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.NewErrorWithCodeErr(err, errs.SQL_ERROR_NO_ROWS)
+	}
+
+	return err
+}
+
+// encodeCursor returns opaque cursor of the column value
+func encodeCursor(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor returns the column value of the cursor, the number is kept as json.Number to avoid precision loss
+func decodeCursor(cursor string) (interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err = decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if number, ok := value.(json.Number); ok {
+		return number.String(), nil
+	}
+
+	return value, nil
+}
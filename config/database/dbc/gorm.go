@@ -0,0 +1,173 @@
+package dbc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"gorm.io/gorm"
+)
+
+const (
+	gormTraceKey = "dbc:trace"
+	gormStartKey = "dbc:start"
+)
+
+type txKey struct{}
+
+// withTx returns context which carry the transaction, so the transaction can be used by sqlx and gorm
+func withTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// Conn returns the transaction of the context started by StartTransaction, otherwise returns db.
+// the repositories use it to join the transaction of the caller, e.g.:
+//
+//	func (r *repository) Save(ctx context.Context, user User) error {
+//		_, err := dbc.Conn(ctx, r.db).Exec(ctx, query, user.Id, user.Name)
+//		return err
+//	}
+func Conn(ctx context.Context, db SqlDbc) SqlDbc {
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// NewGormDB create gorm instance with the logging and tracing callbacks,
+// the sqlx connection must share the same pool of gorm
+func NewGormDB(db *gorm.DB, sqlxDB *DB) (*GormDB, error) {
+	g := &GormDB{DB: db, Sqlx: sqlxDB}
+	if err := g.registerCallbacks(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// WithContext returns gorm session of the context, the session use the transaction
+// when the context is started by StartTransaction (sqlx or gorm)
+func (g *GormDB) WithContext(ctx context.Context) *gorm.DB {
+	db := g.DB.WithContext(ctx)
+	if tx, ok := ctx.Value(txKey{}).(*Tx); ok {
+		db.Statement.ConnPool = tx.DB.Tx
+	}
+
+	return db
+}
+
+// StartTransaction start the transaction with the shared transaction helper of sqlx,
+// the context of txFunc can be used by sqlx (Conn) and gorm (WithContext) in the same transaction.
+// nested call starts a SAVEPOINT
+func (g *GormDB) StartTransaction(ctx context.Context, txFunc func(context.Context, *GormTx) error, opts ...TxOptionFunc) error {
+	return Conn(ctx, g.Sqlx).StartTransaction(ctx, func(ctx context.Context, tx SqlDbc) error {
+		return txFunc(ctx, &GormTx{DB: g.WithContext(ctx), Sqlx: tx})
+	}, opts...)
+}
+
+// Close the connection pool
+func (g *GormDB) Close() error {
+	return g.Sqlx.Close()
+}
+
+// registerCallbacks register the logging and tracing callbacks of gorm
+func (g *GormDB) registerCallbacks() error {
+	callback := g.DB.Callback()
+
+	if err := callback.Create().Before("gorm:create").Register("dbc:before_create", g.before("Create")); err != nil {
+		return err
+	}
+	if err := callback.Create().After("gorm:create").Register("dbc:after_create", g.after); err != nil {
+		return err
+	}
+	if err := callback.Query().Before("gorm:query").Register("dbc:before_query", g.before("Query")); err != nil {
+		return err
+	}
+	if err := callback.Query().After("gorm:query").Register("dbc:after_query", g.after); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("dbc:before_update", g.before("Update")); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("dbc:after_update", g.after); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("dbc:before_delete", g.before("Delete")); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:delete").Register("dbc:after_delete", g.after); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("dbc:before_row", g.before("Row")); err != nil {
+		return err
+	}
+	if err := callback.Row().After("gorm:row").Register("dbc:after_row", g.after); err != nil {
+		return err
+	}
+	if err := callback.Raw().Before("gorm:raw").Register("dbc:before_raw", g.before("Raw")); err != nil {
+		return err
+	}
+
+	return callback.Raw().After("gorm:raw").Register("dbc:after_raw", g.after)
+}
+
+// before start the trace of the statement
+func (g *GormDB) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		name := "Gorm:" + operation
+		if isGormTx(db) {
+			name = "GormTx:" + operation
+		}
+
+		trace, ctx := tracer.StartTraceWithContext(db.Statement.Context, name)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormTraceKey, trace)
+		db.InstanceSet(gormStartKey, time.Now())
+	}
+}
+
+// after log the executed statement and finish the trace
+func (g *GormDB) after(db *gorm.DB) {
+	ctx := db.Statement.Context
+	query := db.Statement.SQL.String()
+	args := db.Statement.Vars
+
+	start := time.Now()
+	if val, ok := db.InstanceGet(gormStartKey); ok {
+		start = val.(time.Time)
+	}
+
+	types := logger.Sql
+	var explainDB explainer = g.Sqlx.DB
+	if isGormTx(db) {
+		types = logger.SqlTx
+		explainDB = nil
+	}
+
+	log := logger.DBContext(ctx, types, query, args...)
+	log.StartAt(start)
+	log.Store(ctx)
+	g.Sqlx.Monitor.observe(ctx, explainDB, query, args, start, db.Error)
+
+	val, ok := db.InstanceGet(gormTraceKey)
+	if !ok {
+		return
+	}
+
+	trace := val.(tracer.Tracer)
+	// log tracer
+	trace.Log("query", query)
+	trace.Log("arguments", log.Arguments)
+	trace.SetTag("rows_affected", db.RowsAffected)
+	trace.SetError(db.Error)
+	trace.Finish()
+}
+
+// isGormTx returns true when the statement run in a transaction
+func isGormTx(db *gorm.DB) bool {
+	_, ok := db.Statement.ConnPool.(*sql.Tx)
+	return ok
+}
@@ -0,0 +1,167 @@
+package dbc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+)
+
+var (
+	fingerprintComment     = regexp.MustCompile(`(?s)/\*.*?\*/|--[^\n]*`)
+	fingerprintString      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fingerprintPlaceholder = regexp.MustCompile(`\$\d+|\?`)
+	fingerprintNumber      = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	fingerprintList        = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	fingerprintValues      = regexp.MustCompile(`(?i)values\s*\(\?\)(?:\s*,\s*\(\?\))*`)
+	fingerprintSpace       = regexp.MustCompile(`\s+`)
+)
+
+// Fingerprint normalize the query by replacing literal values and placeholders with "?",
+// collapsing IN lists and multi-row VALUES, and removing comments and redundant whitespace,
+// so the queries with different values have the same fingerprint
+func Fingerprint(query string) string {
+	q := fingerprintComment.ReplaceAllString(query, " ")
+	q = fingerprintString.ReplaceAllString(q, "?")
+	q = fingerprintPlaceholder.ReplaceAllString(q, "?")
+	q = fingerprintNumber.ReplaceAllString(q, "?")
+	q = fingerprintList.ReplaceAllString(q, "(?)")
+	q = fingerprintValues.ReplaceAllString(q, "VALUES (?)")
+	q = fingerprintSpace.ReplaceAllString(q, " ")
+
+	return strings.TrimSpace(q)
+}
+
+// operation returns the sql command of the query, e.g.: SELECT, INSERT
+func operation(query string) string {
+	fields := strings.Fields(fingerprintComment.ReplaceAllString(query, " "))
+	if len(fields) < 1 {
+		return "UNKNOWN"
+	}
+
+	return strings.ToUpper(strings.TrimLeft(fields[0], "("))
+}
+
+type monitorOption struct {
+	slowThreshold  time.Duration
+	explain        bool
+	explainTimeout time.Duration
+}
+
+// MonitorOptionFunc option func for monitor
+type MonitorOptionFunc func(*monitorOption)
+
+// MonitorOptionSlowThreshold set the duration of slow query, 0 disable the slow query log
+func MonitorOptionSlowThreshold(threshold time.Duration) MonitorOptionFunc {
+	return func(o *monitorOption) {
+		o.slowThreshold = threshold
+	}
+}
+
+// MonitorOptionExplain include the EXPLAIN plan of the slow query in the log.
+// the plan is not executed (without ANALYZE), the query in a failed transaction is not explained
+func MonitorOptionExplain(explain bool) MonitorOptionFunc {
+	return func(o *monitorOption) {
+		o.explain = explain
+	}
+}
+
+// MonitorOptionExplainTimeout set timeout of the EXPLAIN query
+func MonitorOptionExplainTimeout(timeout time.Duration) MonitorOptionFunc {
+	return func(o *monitorOption) {
+		o.explainTimeout = timeout
+	}
+}
+
+// Monitor record the query duration into prometheus and log the slow query
+type Monitor struct {
+	opt monitorOption
+}
+
+// NewMonitor create new monitor of the sql queries
+func NewMonitor(opts ...MonitorOptionFunc) *Monitor {
+	m := &Monitor{
+		opt: monitorOption{
+			slowThreshold:  env.GetDuration("DB_SLOW_QUERY_THRESHOLD", time.Second),
+			explain:        env.GetBool("DB_EXPLAIN_SLOW_QUERY", false),
+			explainTimeout: env.GetDuration("DB_EXPLAIN_TIMEOUT", 5*time.Second),
+		},
+	}
+	for _, opt := range opts {
+		opt(&m.opt)
+	}
+
+	return m
+}
+
+// explainer execute the EXPLAIN query
+type explainer interface {
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+}
+
+// observe record the query, db is used to explain the slow query and can be nil when the query cannot be explained.
+// the statements of the transaction are never explained, the failed EXPLAIN aborts the transaction of the caller.
+// the metrics are recorded even when the monitor is nil
+func (m *Monitor) observe(ctx context.Context, db explainer, query string, args []interface{}, start time.Time, err error) {
+	since := time.Since(start)
+	fingerprint := Fingerprint(query)
+	monitoring.RecordSql(operation(query), fingerprint, err, since)
+
+	if m == nil || m.opt.slowThreshold <= 0 || since < m.opt.slowThreshold {
+		return
+	}
+
+	var plan string
+	if m.opt.explain && db != nil && err == nil && explainable(query) {
+		plan = m.explainPlan(ctx, db, query, args)
+	}
+
+	if plan != "" {
+		logger.Log.Warnf(ctx, "slow query %s (threshold %s): %s\nplan:\n%s", since, m.opt.slowThreshold, fingerprint, plan)
+		return
+	}
+
+	logger.Log.Warnf(ctx, "slow query %s (threshold %s): %s", since, m.opt.slowThreshold, fingerprint)
+}
+
+// explainable returns true when the query can be explained, EXPLAIN of the other statements
+// (e.g.: LOCK TABLE, SET, DDL, TRUNCATE, CALL) returns an error
+func explainable(query string) bool {
+	switch operation(query) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+		return true
+	default:
+		return false
+	}
+}
+
+// explainPlan returns the EXPLAIN plan of the query
+func (m *Monitor) explainPlan(ctx context.Context, db explainer, query string, args []interface{}) string {
+	// the query context may be already done
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.opt.explainTimeout)
+	defer cancel()
+
+	rows, err := db.QueryxContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return fmt.Sprintf("failed to explain: %s", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			return fmt.Sprintf("failed to explain: %s", err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
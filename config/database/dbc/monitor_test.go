@@ -0,0 +1,31 @@
+package dbc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainable(t *testing.T) {
+	tests := map[string]bool{
+		"SELECT * FROM users":                         true,
+		"  insert into users (id) values ($1)":        true,
+		"UPDATE users SET name = $1":                  true,
+		"DELETE FROM users WHERE id = $1":             true,
+		"WITH u AS (SELECT 1) SELECT * FROM u":        true,
+		"(SELECT 1) UNION (SELECT 2)":                 true,
+		"/* service:users */ SELECT 1":                true,
+		"LOCK TABLE users IN ACCESS EXCLUSIVE MODE":   false,
+		"SET LOCAL statement_timeout = '1s'":          false,
+		"TRUNCATE users":                              false,
+		"CREATE INDEX users_name_idx ON users (name)": false,
+		"CALL refresh_users()":                        false,
+		"":                                            false,
+	}
+
+	for query, want := range tests {
+		t.Run(query, func(t *testing.T) {
+			assert.Equal(t, want, explainable(query))
+		})
+	}
+}
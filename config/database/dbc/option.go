@@ -0,0 +1,60 @@
+package dbc
+
+import (
+	"database/sql"
+	"time"
+)
+
+// txOption options of StartTransaction
+type txOption struct {
+	isolation    sql.IsolationLevel
+	readOnly     bool
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// TxOptionFunc option func for StartTransaction
+type TxOptionFunc func(*txOption)
+
+// TxOptionIsolation set isolation level of the transaction, e.g.: sql.LevelRepeatableRead, sql.LevelSerializable
+func TxOptionIsolation(level sql.IsolationLevel) TxOptionFunc {
+	return func(o *txOption) {
+		o.isolation = level
+	}
+}
+
+// TxOptionReadOnly set the transaction as read only
+func TxOptionReadOnly() TxOptionFunc {
+	return func(o *txOption) {
+		o.readOnly = true
+	}
+}
+
+// TxOptionRetry replay the transaction with a fresh transaction when postgres returns
+// serialization failure (40001) or deadlock detected (40P01), up to maxAttempts (including the first attempt).
+// the delay between attempts is doubled from backoff
+func TxOptionRetry(maxAttempts int, backoff time.Duration) TxOptionFunc {
+	return func(o *txOption) {
+		o.maxAttempts = maxAttempts
+		o.retryBackoff = backoff
+	}
+}
+
+// getTxOption apply all options
+func getTxOption(opts ...TxOptionFunc) txOption {
+	o := txOption{maxAttempts: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.maxAttempts < 1 {
+		o.maxAttempts = 1
+	}
+
+	return o
+}
+
+// txOptions returns sql.TxOptions of the transaction
+func (o txOption) txOptions() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: o.isolation, ReadOnly: o.readOnly}
+}
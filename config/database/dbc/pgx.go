@@ -0,0 +1,154 @@
+package dbc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/tracer"
+)
+
+// PgxDB is an instance non-transactional connection with pgx driver,
+// all SqlDbc methods use the database/sql connection of the same pool
+type PgxDB struct {
+	DB
+	Pool *pgxpool.Pool
+}
+
+// BatchQuery a query of the batch
+type BatchQuery struct {
+	Query string
+	Args  []interface{}
+}
+
+// Notification received from LISTEN
+type Notification struct {
+	PID     uint32
+	Channel string
+	Payload string
+}
+
+// PgxDbc is SqlDbc with pgx specific features
+type PgxDbc interface {
+	SqlDbc
+
+	// CopyFrom bulk insert the rows with COPY protocol, returns the number of inserted rows.
+	// table can be prefixed with schema, e.g.: public.users
+	CopyFrom(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, error)
+
+	// SendBatch send all queries in a single round trip (pipeline), returns rows affected of each query.
+	// the batch runs in an implicit transaction, all queries are rolled back when one of them fails
+	SendBatch(ctx context.Context, queries ...BatchQuery) ([]int64, error)
+
+	// Listen subscribe the channel with a dedicated connection and call handler on every notification,
+	// block until the context is done or the handler returns error
+	Listen(ctx context.Context, channel string, handler func(context.Context, Notification) error) error
+
+	// Notify send notification into the channel
+	Notify(ctx context.Context, channel, payload string) error
+}
+
+func (d *PgxDB) CopyFrom(ctx context.Context, table string, columns []string, rows [][]interface{}) (count int64, err error) {
+	var log logger.Database
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:CopyFrom")
+	defer func() {
+		log.Store(ctx)
+		trace.SetError(err)
+		trace.Finish()
+	}()
+	log = logger.DBContext(ctx, logger.Sql, fmt.Sprintf("COPY %s (%s) FROM STDIN", table, strings.Join(columns, ", ")), fmt.Sprintf("rows: %d", len(rows)))
+
+	// log tracer
+	trace.Log("table", table)
+	trace.Log("columns", columns)
+	trace.Log("rows", len(rows))
+
+	count, err = d.Pool.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, pgx.CopyFromRows(rows))
+	return
+}
+
+func (d *PgxDB) SendBatch(ctx context.Context, queries ...BatchQuery) (rowsAffected []int64, err error) {
+	var log logger.Database
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Sql:SendBatch")
+	defer func() {
+		log.Store(ctx)
+		trace.SetError(err)
+		trace.Finish()
+	}()
+
+	batch := new(pgx.Batch)
+	statements := make([]string, 0, len(queries))
+	for _, q := range queries {
+		batch.Queue(q.Query, q.Args...)
+		statements = append(statements, q.Query)
+	}
+	log = logger.DBContext(ctx, logger.Sql, strings.Join(statements, "; "), fmt.Sprintf("queries: %d", len(queries)))
+
+	// log tracer
+	trace.Log("queries", statements)
+
+	results := d.Pool.SendBatch(ctx, batch)
+	defer func() {
+		if e := results.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	for i := range queries {
+		tag, e := results.Exec()
+		if e != nil {
+			err = fmt.Errorf("batch query %d: %w", i, e)
+			return
+		}
+
+		rowsAffected = append(rowsAffected, tag.RowsAffected())
+	}
+
+	return
+}
+
+func (d *PgxDB) Listen(ctx context.Context, channel string, handler func(context.Context, Notification) error) error {
+	conn, err := d.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	defer func() {
+		// the context may be done, unlisten with a new context before the connection back to the pool
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+pgx.Identifier{channel}.Sanitize())
+	}()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		if err = handler(ctx, Notification{PID: n.PID, Channel: n.Channel, Payload: n.Payload}); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *PgxDB) Notify(ctx context.Context, channel, payload string) (err error) {
+	_, err = d.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+	return
+}
+
+// Close the database/sql connection and the pool
+func (d *PgxDB) Close() error {
+	err := d.DB.Close()
+	d.Pool.Close()
+
+	return err
+}
@@ -0,0 +1,408 @@
+package dbc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mqdvi-dp/go-common/logger"
+)
+
+// replicationLagQuery returns replication lag of the replica in seconds,
+// the lag is grown when the master is idle because there is no transaction to replay
+const replicationLagQuery = `SELECT COALESCE(EXTRACT(EPOCH FROM (now() - pg_last_xact_replay_timestamp())), 0)`
+
+// Balancer strategy to select the replica
+type Balancer string
+
+const (
+	// RoundRobin select the healthy replicas in turn
+	RoundRobin Balancer = "round-robin"
+	// LeastLatency select the healthy replica with the lowest latency
+	LeastLatency Balancer = "least-latency"
+)
+
+// defaultEjectDuration how long the broken replica is ejected when there is no health check
+const defaultEjectDuration = 30 * time.Second
+
+type routerOption struct {
+	balancer            Balancer
+	readYourWrites      time.Duration
+	healthCheckInterval time.Duration
+	maxReplicationLag   time.Duration
+	ejectDuration       time.Duration
+}
+
+// RouterOptionFunc option func for router
+type RouterOptionFunc func(*routerOption)
+
+// RouterOptionBalancer set strategy to select the replica, default is RoundRobin
+func RouterOptionBalancer(balancer Balancer) RouterOptionFunc {
+	return func(o *routerOption) {
+		o.balancer = balancer
+	}
+}
+
+// RouterOptionReadYourWrites pin the reads to master within window after a write in the same session,
+// the session is created by WithSession
+func RouterOptionReadYourWrites(window time.Duration) RouterOptionFunc {
+	return func(o *routerOption) {
+		o.readYourWrites = window
+	}
+}
+
+// RouterOptionHealthCheck ping the replicas every interval, the unhealthy replicas are ejected until the ping succeed
+func RouterOptionHealthCheck(interval time.Duration) RouterOptionFunc {
+	return func(o *routerOption) {
+		o.healthCheckInterval = interval
+	}
+}
+
+// RouterOptionEjectDuration set how long the replica with broken connection is ejected when the health check
+// is disabled, the replica is tried again after the duration. default is 30 seconds
+func RouterOptionEjectDuration(duration time.Duration) RouterOptionFunc {
+	return func(o *routerOption) {
+		o.ejectDuration = duration
+	}
+}
+
+// RouterOptionMaxReplicationLag eject the replica when the replication lag exceed maxLag, checked on every health check
+func RouterOptionMaxReplicationLag(maxLag time.Duration) RouterOptionFunc {
+	return func(o *routerOption) {
+		o.maxReplicationLag = maxLag
+	}
+}
+
+type sessionKey struct{}
+
+// session keep the last write time in the context
+type session struct {
+	lastWrite atomic.Int64
+}
+
+// WithSession returns context which remember the last write, used by the read-your-writes of the router
+func WithSession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+// replica connection with the health status
+type replica struct {
+	name    string
+	db      SqlDbc
+	healthy atomic.Bool
+	// retryAt the ejected replica is tried again at (unix nano), used when there is no health check
+	retryAt atomic.Int64
+	// latency moving average in nanosecond
+	latency atomic.Int64
+}
+
+// observe update the moving average of latency
+func (r *replica) observe(d time.Duration) {
+	old := r.latency.Load()
+	if old == 0 {
+		r.latency.Store(int64(d))
+		return
+	}
+
+	r.latency.Store(old - old/5 + int64(d)/5)
+}
+
+// Router is SqlDbc which routes the reads (Get, Select, Queryx) to the healthy replicas
+// and the writes or transactions to master
+type Router struct {
+	master   SqlDbc
+	replicas []*replica
+	opt      routerOption
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRouter create new router of master and replicas,
+// the reads go to master when there is no healthy replica
+func NewRouter(master SqlDbc, replicas []SqlDbc, opts ...RouterOptionFunc) *Router {
+	r := &Router{
+		master: master,
+		opt:    routerOption{balancer: RoundRobin, ejectDuration: defaultEjectDuration},
+		stop:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(&r.opt)
+	}
+
+	for i, db := range replicas {
+		rep := &replica{name: fmt.Sprintf("replica-%d", i+1), db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	if r.opt.healthCheckInterval > 0 && len(r.replicas) > 0 {
+		go r.healthCheck()
+	}
+
+	return r
+}
+
+// healthCheck ping the replicas and check the replication lag periodically
+func (r *Router) healthCheck() {
+	ticker := time.NewTicker(r.opt.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		for _, rep := range r.replicas {
+			healthy := r.checkReplica(rep)
+			if rep.healthy.Swap(healthy) != healthy {
+				if healthy {
+					logger.GreenItalic(fmt.Sprintf("sql router: %s is healthy, added back", rep.name))
+				} else {
+					logger.Red(fmt.Sprintf("sql router: %s is unhealthy, ejected", rep.name))
+				}
+			}
+		}
+	}
+}
+
+func (r *Router) checkReplica(rep *replica) bool {
+	start := time.Now()
+	if err := rep.db.Ping(); err != nil {
+		return false
+	}
+	rep.observe(time.Since(start))
+
+	if r.opt.maxReplicationLag <= 0 {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.opt.healthCheckInterval)
+	defer cancel()
+
+	var lag float64
+	if err := rep.db.Get(ctx, &lag, replicationLagQuery); err != nil {
+		return false
+	}
+
+	return time.Duration(lag*float64(time.Second)) <= r.opt.maxReplicationLag
+}
+
+// available returns true when the replica can serve the read, the ejected replica is available again
+// after the eject duration when there is no health check
+func (r *Router) available(rep *replica) bool {
+	if rep.healthy.Load() {
+		return true
+	}
+	if r.opt.healthCheckInterval > 0 {
+		return false
+	}
+
+	retryAt := rep.retryAt.Load()
+	return retryAt > 0 && time.Now().UnixNano() >= retryAt
+}
+
+// eject the replica with broken connection, the health check adds back the replica when the ping succeed,
+// otherwise the replica is tried again after the eject duration
+func (r *Router) eject(rep *replica, err error) {
+	rep.healthy.Store(false)
+	if r.opt.healthCheckInterval > 0 {
+		logger.Red(fmt.Sprintf("sql router: %s is unhealthy, ejected: %s", rep.name, err))
+		return
+	}
+
+	rep.retryAt.Store(time.Now().Add(r.opt.ejectDuration).UnixNano())
+	logger.Red(fmt.Sprintf("sql router: %s is unhealthy, ejected for %s: %s", rep.name, r.opt.ejectDuration, err))
+}
+
+// replica select the replica for read, returns nil when the read must go to master
+func (r *Router) replica(ctx context.Context) *replica {
+	if r.opt.readYourWrites > 0 {
+		if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+			if last := s.lastWrite.Load(); last > 0 && time.Since(time.Unix(0, last)) < r.opt.readYourWrites {
+				return nil
+			}
+		}
+	}
+
+	var selected *replica
+	switch r.opt.balancer {
+	case LeastLatency:
+		for _, rep := range r.replicas {
+			if !r.available(rep) {
+				continue
+			}
+
+			if selected == nil || rep.latency.Load() < selected.latency.Load() {
+				selected = rep
+			}
+		}
+	default:
+		n := uint64(len(r.replicas))
+		start := r.next.Add(1)
+		for i := uint64(0); i < n; i++ {
+			rep := r.replicas[(start+i)%n]
+			if r.available(rep) {
+				selected = rep
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+// wrote remember the write for read-your-writes
+func (r *Router) wrote(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.lastWrite.Store(time.Now().UnixNano())
+	}
+}
+
+// read execute the read to the replica, fail over to master when the replica connection is broken
+func (r *Router) read(ctx context.Context, fn func(db SqlDbc) error) error {
+	rep := r.replica(ctx)
+	if rep == nil {
+		return fn(r.master)
+	}
+
+	start := time.Now()
+	err := fn(rep.db)
+	if err == nil || !isConnectionError(err) {
+		rep.observe(time.Since(start))
+		// the ejected replica is added back by the read when there is no health check
+		if r.opt.healthCheckInterval <= 0 && !rep.healthy.Swap(true) {
+			logger.GreenItalic(fmt.Sprintf("sql router: %s is healthy, added back", rep.name))
+		}
+		return err
+	}
+
+	r.eject(rep, err)
+	return fn(r.master)
+}
+
+// isConnectionError returns true when the connection is broken
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
+
+func (r *Router) Queryx(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
+	err = r.read(ctx, func(db SqlDbc) error {
+		rows, err = db.Queryx(ctx, query, args...)
+		return err
+	})
+
+	return
+}
+
+// QueryRowx the error is returned by the row, no fail over to master
+func (r *Router) QueryRowx(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	if rep := r.replica(ctx); rep != nil {
+		return rep.db.QueryRowx(ctx, query, args...)
+	}
+
+	return r.master.QueryRowx(ctx, query, args...)
+}
+
+func (r *Router) Preparex(ctx context.Context, query string, args ...interface{}) error {
+	defer r.wrote(ctx)
+
+	return r.master.Preparex(ctx, query, args...)
+}
+
+func (r *Router) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.read(ctx, func(db SqlDbc) error {
+		return db.Get(ctx, dest, query, args...)
+	})
+}
+
+func (r *Router) GetWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.read(ctx, func(db SqlDbc) error {
+		return db.GetWithIn(ctx, dest, query, args...)
+	})
+}
+
+func (r *Router) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.read(ctx, func(db SqlDbc) error {
+		return db.Select(ctx, dest, query, args...)
+	})
+}
+
+func (r *Router) SelectWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return r.read(ctx, func(db SqlDbc) error {
+		return db.SelectWithIn(ctx, dest, query, args...)
+	})
+}
+
+// Master returns the master connection, e.g.: the writes with RETURNING which are executed by Get or Select
+func (r *Router) Master() SqlDbc {
+	return r.master
+}
+
+func (r *Router) Rebind(query string) string {
+	return r.master.Rebind(query)
+}
+
+func (r *Router) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer r.wrote(ctx)
+
+	return r.master.Exec(ctx, query, args...)
+}
+
+func (r *Router) NamedExec(ctx context.Context, query string, args interface{}) (sql.Result, error) {
+	defer r.wrote(ctx)
+
+	return r.master.NamedExec(ctx, query, args)
+}
+
+func (r *Router) MustBegin() *Tx {
+	return r.master.MustBegin()
+}
+
+// Close stop the health check and close master and replicas connection
+func (r *Router) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+
+	err := r.master.Close()
+	for _, rep := range r.replicas {
+		if e := rep.db.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (r *Router) Ping() error {
+	return r.master.Ping()
+}
+
+// StartTransaction always run on master
+func (r *Router) StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error, opts ...TxOptionFunc) error {
+	defer r.wrote(ctx)
+
+	return r.master.StartTransaction(ctx, txFunc, opts...)
+}
+
+func (r *Router) Rollback() error {
+	return r.master.Rollback()
+}
+
+func (r *Router) Commit() error {
+	return r.master.Commit()
+}
@@ -0,0 +1,70 @@
+package dbc
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubDbc is SqlDbc which counts the reads and returns the error
+type stubDbc struct {
+	SqlDbc
+	reads int
+	err   error
+}
+
+func (s *stubDbc) Get(_ context.Context, _ interface{}, _ string, _ ...interface{}) error {
+	s.reads++
+	return s.err
+}
+
+func TestRouterEjectWithoutHealthCheck(t *testing.T) {
+	master, rep := &stubDbc{}, &stubDbc{err: driver.ErrBadConn}
+	r := NewRouter(master, []SqlDbc{rep}, RouterOptionEjectDuration(50*time.Millisecond))
+	ctx := context.Background()
+
+	// the broken replica is ejected and the read fails over to master
+	assert.NoError(t, r.Get(ctx, nil, "SELECT 1"))
+	assert.Equal(t, 1, rep.reads)
+	assert.Equal(t, 1, master.reads)
+
+	// the ejected replica is skipped
+	assert.NoError(t, r.Get(ctx, nil, "SELECT 1"))
+	assert.Equal(t, 1, rep.reads)
+	assert.Equal(t, 2, master.reads)
+
+	// the replica is tried again after the eject duration and added back
+	rep.err = nil
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, r.Get(ctx, nil, "SELECT 1"))
+	assert.Equal(t, 2, rep.reads)
+	assert.True(t, r.replicas[0].healthy.Load())
+}
+
+func TestRouterEjectWithHealthCheck(t *testing.T) {
+	master, rep := &stubDbc{}, &stubDbc{err: driver.ErrBadConn}
+	r := NewRouter(master, nil, RouterOptionHealthCheck(time.Hour), RouterOptionEjectDuration(time.Millisecond))
+	r.replicas = []*replica{{name: "replica-1", db: rep}}
+	r.replicas[0].healthy.Store(true)
+	ctx := context.Background()
+
+	assert.NoError(t, r.Get(ctx, nil, "SELECT 1"))
+	time.Sleep(5 * time.Millisecond)
+
+	// only the health check adds back the replica
+	assert.NoError(t, r.Get(ctx, nil, "SELECT 1"))
+	assert.Equal(t, 1, rep.reads)
+	assert.Equal(t, 2, master.reads)
+}
+
+func TestRouterQueryErrorDoesNotEject(t *testing.T) {
+	master, rep := &stubDbc{}, &stubDbc{err: ErrNoRowsAffected}
+	r := NewRouter(master, []SqlDbc{rep})
+
+	assert.ErrorIs(t, r.Get(context.Background(), nil, "SELECT 1"), ErrNoRowsAffected)
+	assert.True(t, r.replicas[0].healthy.Load())
+	assert.Equal(t, 0, master.reads)
+}
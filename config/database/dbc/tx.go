@@ -3,6 +3,8 @@ package dbc
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/mqdvi-dp/go-common/logger"
@@ -12,12 +14,14 @@ import (
 func (d *Tx) Queryx(ctx context.Context, query string, args ...interface{}) (rows *sqlx.Rows, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:Queryx")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.SqlTx, query, args...)
+	log = logger.DBContext(ctx, logger.SqlTx, query, args...)
 
 	rows, err = d.DB.QueryxContext(ctx, query, args...)
 	return
@@ -26,15 +30,17 @@ func (d *Tx) Queryx(ctx context.Context, query string, args ...interface{}) (row
 func (d *Tx) QueryRowx(ctx context.Context, query string, args ...interface{}) (row *sqlx.Row) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:QueryRowx")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, row.Err())
 		trace.Finish()
 	}()
-	log = logger.DB(logger.SqlTx, query, args...)
+	log = logger.DBContext(ctx, logger.SqlTx, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	row = d.DB.QueryRowxContext(ctx, query, args...)
 	return
@@ -43,15 +49,17 @@ func (d *Tx) QueryRowx(ctx context.Context, query string, args ...interface{}) (
 func (d *Tx) Exec(ctx context.Context, query string, args ...interface{}) (row sql.Result, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:Exec")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("exec", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 	res, err := d.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		trace.SetError(err)
@@ -63,15 +71,17 @@ func (d *Tx) Exec(ctx context.Context, query string, args ...interface{}) (row s
 func (d *Tx) NamedExec(ctx context.Context, query string, args interface{}) (row sql.Result, err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:NamedExec")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, nil, start, err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args)
+	log = logger.DBContext(ctx, logger.Sql, query, args)
 
 	// log tracer
 	trace.Log("exec", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 	res, err := d.DB.NamedExecContext(ctx, query, args)
 	if err != nil {
 		trace.SetError(err)
@@ -83,16 +93,18 @@ func (d *Tx) NamedExec(ctx context.Context, query string, args interface{}) (row
 func (d *Tx) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:Get")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.SqlTx, query, args...)
+	log = logger.DBContext(ctx, logger.SqlTx, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	err = d.DB.GetContext(ctx, dest, query, args...)
 	return
@@ -101,16 +113,18 @@ func (d *Tx) Get(ctx context.Context, dest interface{}, query string, args ...in
 func (d *Tx) GetWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:GetWithIn")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create parameters "in"
 	query, args, err = sqlx.In(query, args...)
@@ -129,16 +143,18 @@ func (d *Tx) GetWithIn(ctx context.Context, dest interface{}, query string, args
 func (d *Tx) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:Select")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.SqlTx, query, args...)
+	log = logger.DBContext(ctx, logger.SqlTx, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	err = d.DB.SelectContext(ctx, dest, query, args...)
 	return
@@ -147,16 +163,18 @@ func (d *Tx) Select(ctx context.Context, dest interface{}, query string, args ..
 func (d *Tx) SelectWithIn(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:SelectWithIn")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.Sql, query, args...)
+	log = logger.DBContext(ctx, logger.Sql, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create parameters "in"
 	query, args, err = sqlx.In(query, args...)
@@ -175,16 +193,18 @@ func (d *Tx) SelectWithIn(ctx context.Context, dest interface{}, query string, a
 func (d *Tx) Preparex(ctx context.Context, query string, args ...interface{}) (err error) {
 	var log logger.Database
 	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:Preparex")
+	start := time.Now()
 	defer func() {
 		log.Store(ctx)
+		d.monitor.observe(ctx, nil, query, args, start, err)
 		trace.SetError(err)
 		trace.Finish()
 	}()
-	log = logger.DB(logger.SqlTx, query, args...)
+	log = logger.DBContext(ctx, logger.SqlTx, query, args...)
 
 	// log tracer
 	trace.Log("query", query)
-	trace.Log("arguments", args)
+	trace.Log("arguments", log.Arguments)
 
 	// create statement query
 	stmt, err := d.DB.PreparexContext(ctx, query)
@@ -232,14 +252,60 @@ func (d *Tx) Ping() error {
 	return nil
 }
 
-func (d *Tx) StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error) error {
-	return nil
+// StartTransaction starts a nested transaction with SAVEPOINT, the options are ignored
+func (d *Tx) StartTransaction(ctx context.Context, txFunc func(context.Context, SqlDbc) error, _ ...TxOptionFunc) (err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "SqlTx:StartTransaction")
+	defer trace.Finish()
+
+	tx := &Tx{DB: d.DB, savepoint: d.savepoint + 1, monitor: d.monitor}
+	trace.Log("savepoint", tx.savepointName())
+
+	if _, err = d.DB.ExecContext(ctx, "SAVEPOINT "+tx.savepointName()); err != nil {
+		trace.SetError(err)
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s", r)
+		}
+
+		if err != nil {
+			trace.SetError(err)
+			_ = tx.Rollback()
+			return
+		}
+
+		if err = tx.Commit(); err != nil {
+			trace.SetError(err)
+		}
+	}()
+
+	err = txFunc(withTx(ctx, tx), tx)
+	return err
 }
 
+// Rollback a transaction, rollback to the savepoint for nested transaction
 func (d *Tx) Rollback() error {
+	if d.savepoint > 0 {
+		_, err := d.DB.Exec("ROLLBACK TO SAVEPOINT " + d.savepointName())
+		return err
+	}
+
 	return d.DB.Rollback()
 }
 
+// Commit a transaction, release the savepoint for nested transaction
 func (d *Tx) Commit() error {
+	if d.savepoint > 0 {
+		_, err := d.DB.Exec("RELEASE SAVEPOINT " + d.savepointName())
+		return err
+	}
+
 	return d.DB.Commit()
 }
+
+// savepointName returns unique name of savepoint for each nested level
+func (d *Tx) savepointName() string {
+	return fmt.Sprintf("sp_%d", d.savepoint)
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mqdvi-dp/go-common/abstract"
+	"github.com/mqdvi-dp/go-common/config/database/dbc"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+var gormMap = make(map[string]*dbc.GormDB)
+
+// gormInstance instance variables used by the gorm database
+type gormInstance struct {
+	db *dbc.GormDB
+}
+
+// Database get abstraction sqlx of the same pool
+func (g *gormInstance) Database() dbc.SqlDbc {
+	return g.db.Sqlx
+}
+
+// Gorm get the gorm connection
+func (g *gormInstance) Gorm() *dbc.GormDB {
+	return g.db
+}
+
+// Disconnect close the database connection
+func (g *gormInstance) Disconnect(ctx context.Context) error {
+	logger.RedBold("postgres (gorm): disconnecting...")
+	defer fmt.Printf("\x1b[31;1mPostgres (Gorm) Disconnecting:\x1b[0m \x1b[32;1mSUCCESS\x1b[0m\n")
+
+	return g.db.Close()
+}
+
+// NewGormConnection creates a gorm connection, the sqlx connection (Database) share the same pool,
+// so the transaction of StartTransaction can be used by gorm and sqlx
+func NewGormConnection(opts ...SqlFuncOption) (abstract.GormDatabase, error) {
+	logger.YellowItalic("Load postgresql (gorm) connection...")
+
+	// sql custom option
+	opt := defaultSqlOption()
+	// set option from parameters
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	switch opt.driver {
+	case DriverPq, DriverPgx:
+	default:
+		return nil, fmt.Errorf("unsupported sql driver %s", opt.driver)
+	}
+
+	// if connection already declare, use that
+	if db, ok := gormMap[opt.dsn]; ok {
+		logger.GreenItalic("postgresql (gorm) connected!")
+		return &gormInstance{db: db}, nil
+	}
+
+	client, err := sqlx.Open(string(opt.driver), opt.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// check connection
+	if err = client.Ping(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	client.SetMaxIdleConns(opt.maxIdleConnection)
+	client.SetMaxOpenConns(opt.maxConnection)
+	client.SetConnMaxIdleTime(opt.maxIdleTime)
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: client.DB}), &gorm.Config{
+		// the statements are logged by the callbacks
+		Logger: gormLogger.Default.LogMode(gormLogger.Silent),
+	})
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	db, err := dbc.NewGormDB(gdb, &dbc.DB{DB: client, Monitor: opt.monitor()})
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	// record the connection pool stats
+	monitoring.RegisterSqlStats(opt.statsName(), client.DB)
+
+	// store connection into hashMap
+	gormMap[opt.dsn] = db
+	logger.GreenItalic("postgresql (gorm) connected!")
+	return &gormInstance{db: db}, nil
+}
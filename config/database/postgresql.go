@@ -3,34 +3,125 @@ package database
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/mqdvi-dp/go-common/abstract"
 	"github.com/mqdvi-dp/go-common/config/database/dbc"
 	"github.com/mqdvi-dp/go-common/env"
 	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
 )
 
-var sqlMap = make(map[string]*sqlx.DB)
+var (
+	sqlMap = make(map[string]*sqlx.DB)
+	pgxMap = make(map[string]*dbc.PgxDB)
+)
+
+// SqlDriver driver of the sql connection
+type SqlDriver string
+
+const (
+	// DriverPq lib/pq driver (default)
+	DriverPq SqlDriver = "postgres"
+	// DriverPgx jackc/pgx driver, the database is dbc.PgxDbc which support COPY, batch and LISTEN/NOTIFY
+	DriverPgx SqlDriver = "pgx"
+)
 
 type SqlFuncOption func(*sqlOption)
 
 type sqlOption struct {
-	dsn               string
-	maxIdleTime       time.Duration
-	maxIdleConnection int
-	maxConnection     int
+	dsn                    string
+	driver                 SqlDriver
+	maxIdleTime            time.Duration
+	maxIdleConnection      int
+	maxConnection          int
+	statementCacheCapacity int
+	name                   string
+	slowQueryThreshold     time.Duration
+	explainSlowQuery       bool
+	replicaDsns            []string
+	routerOptions          []dbc.RouterOptionFunc
 }
 
 func defaultSqlOption() sqlOption {
 	return sqlOption{
-		dsn:               env.GetString("DSN_MASTER", "postgres://127.0.0.1:5432"),
-		maxIdleTime:       time.Duration(1) * time.Minute,
-		maxIdleConnection: 5,
-		maxConnection:     env.GetInt("DB_MAX_CONNECTION", 20),
+		dsn:                    env.GetString("DSN_MASTER", "postgres://127.0.0.1:5432"),
+		driver:                 SqlDriver(env.GetString("DB_DRIVER", string(DriverPq))),
+		maxIdleTime:            time.Duration(1) * time.Minute,
+		maxIdleConnection:      5,
+		maxConnection:          env.GetInt("DB_MAX_CONNECTION", 20),
+		statementCacheCapacity: env.GetInt("DB_STATEMENT_CACHE_CAPACITY", 512),
+		slowQueryThreshold:     env.GetDuration("DB_SLOW_QUERY_THRESHOLD", time.Second),
+		explainSlowQuery:       env.GetBool("DB_EXPLAIN_SLOW_QUERY", false),
+		replicaDsns:            env.GetListString("DSN_REPLICAS"),
+	}
+}
+
+// getRouterOptions returns the options of the router from env, the options from SetSqlReplicas are applied after
+func (o sqlOption) getRouterOptions() []dbc.RouterOptionFunc {
+	opts := []dbc.RouterOptionFunc{
+		dbc.RouterOptionBalancer(dbc.Balancer(env.GetString("DB_ROUTER_BALANCER", string(dbc.RoundRobin)))),
+		dbc.RouterOptionReadYourWrites(env.GetDuration("DB_ROUTER_READ_YOUR_WRITES", 0)),
+		dbc.RouterOptionHealthCheck(env.GetDuration("DB_ROUTER_HEALTH_CHECK_INTERVAL", 0)),
+		dbc.RouterOptionMaxReplicationLag(env.GetDuration("DB_ROUTER_MAX_REPLICATION_LAG", 0)),
+	}
+
+	return append(opts, o.routerOptions...)
+}
+
+// monitor returns the monitor of slow queries
+func (o sqlOption) monitor() *dbc.Monitor {
+	return dbc.NewMonitor(
+		dbc.MonitorOptionSlowThreshold(o.slowQueryThreshold),
+		dbc.MonitorOptionExplain(o.explainSlowQuery),
+	)
+}
+
+// statsName returns name of the database in the pool stats metrics,
+// default is the database name and the address of the dsn (e.g.: users@10.0.0.1:5432), so master and replicas
+// of the same database are not collided
+func (o sqlOption) statsName() string {
+	if o.name != "" {
+		return o.name
 	}
+
+	var name, host, port string
+	if u, err := url.Parse(o.dsn); err == nil && u.Scheme != "" {
+		name, host, port = strings.TrimPrefix(u.Path, "/"), u.Hostname(), u.Port()
+	} else {
+		// key=value dsn, e.g.: host=localhost port=5432 dbname=users
+		for _, field := range strings.Fields(o.dsn) {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "dbname":
+				name = value
+			case "host":
+				host = value
+			case "port":
+				port = value
+			}
+		}
+	}
+
+	if name == "" {
+		name = "postgres"
+	}
+	if host == "" {
+		return name
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	return name + "@" + host
 }
 
 // sqlxInstance instance variables used by the sqlx database
@@ -51,13 +142,10 @@ func (s *sqlxInstance) Disconnect(ctx context.Context) error {
 	return s.db.Close()
 }
 
-// NewSqlxConnection creates a SqlxConnection
+// NewSqlxConnection creates a SqlxConnection, when the replicas are set (SetSqlReplicas or env DSN_REPLICAS)
+// the database is dbc.Router which routes the reads to the replicas and the writes to master
 func NewSqlxConnection(opts ...SqlFuncOption) (abstract.SQLDatabase, error) {
 	logger.YellowItalic("Load postgresql connection...")
-	var (
-		client *sqlx.DB
-		err    error
-	)
 
 	// sql custom option
 	opt := defaultSqlOption()
@@ -66,11 +154,58 @@ func NewSqlxConnection(opts ...SqlFuncOption) (abstract.SQLDatabase, error) {
 		o(&opt)
 	}
 
+	master, err := newSqlConnection(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var replicas []dbc.SqlDbc
+	for i, dsn := range opt.replicaDsns {
+		if dsn = strings.TrimSpace(dsn); dsn == "" {
+			continue
+		}
+
+		replicaOpt := opt
+		replicaOpt.dsn = dsn
+		if opt.name != "" {
+			replicaOpt.name = fmt.Sprintf("%s-replica-%d", opt.name, i+1)
+		}
+
+		replica, err := newSqlConnection(replicaOpt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect replica %d: %w", i+1, err)
+		}
+		replicas = append(replicas, replica.Database())
+	}
+
+	if len(replicas) == 0 {
+		return master, nil
+	}
+
+	logger.GreenItalic(fmt.Sprintf("postgresql router with %d replicas!", len(replicas)))
+	return &sqlxInstance{db: dbc.NewRouter(master.Database(), replicas, opt.getRouterOptions()...)}, nil
+}
+
+// newSqlConnection creates a connection of the dsn
+func newSqlConnection(opt sqlOption) (abstract.SQLDatabase, error) {
+	var (
+		client *sqlx.DB
+		err    error
+	)
+
+	switch opt.driver {
+	case DriverPq:
+	case DriverPgx:
+		return newPgxConnection(opt)
+	default:
+		return nil, fmt.Errorf("unsupported sql driver %s", opt.driver)
+	}
+
 	// if connection already declare, use that
 	client, ok := sqlMap[opt.dsn]
 	if ok {
 		logger.GreenItalic("postgresql connected!")
-		return &sqlxInstance{db: &dbc.DB{DB: client}}, nil
+		return &sqlxInstance{db: &dbc.DB{DB: client, Monitor: opt.monitor()}}, nil
 	}
 
 	// initiate connection with postgres
@@ -89,10 +224,55 @@ func NewSqlxConnection(opts ...SqlFuncOption) (abstract.SQLDatabase, error) {
 	client.SetMaxOpenConns(opt.maxConnection)
 	client.SetConnMaxIdleTime(opt.maxIdleTime)
 
+	// record the connection pool stats
+	monitoring.RegisterSqlStats(opt.statsName(), client.DB)
+
 	// store connection into hashMap
 	sqlMap[opt.dsn] = client
 	logger.GreenItalic("postgresql connected!")
-	return &sqlxInstance{db: &dbc.DB{DB: client}}, nil
+	return &sqlxInstance{db: &dbc.DB{DB: client, Monitor: opt.monitor()}}, nil
+}
+
+// newPgxConnection creates a connection with pgx pool, the database/sql connection share the same pool
+func newPgxConnection(opt sqlOption) (abstract.SQLDatabase, error) {
+	// if connection already declare, use that
+	if db, ok := pgxMap[opt.dsn]; ok {
+		logger.GreenItalic("postgresql connected!")
+		return &sqlxInstance{db: db}, nil
+	}
+
+	cfg, err := pgxpool.ParseConfig(opt.dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxConns = int32(opt.maxConnection)
+	cfg.MaxConnIdleTime = opt.maxIdleTime
+	// statement caching, disabled when the capacity is 0 (e.g.: behind pgbouncer transaction pooling)
+	cfg.ConnConfig.StatementCacheCapacity = opt.statementCacheCapacity
+	if opt.statementCacheCapacity < 1 {
+		cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// check connection
+	if err = pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	db := &dbc.PgxDB{DB: dbc.DB{DB: sqlx.NewDb(stdlib.OpenDBFromPool(pool), string(DriverPgx)), Monitor: opt.monitor()}, Pool: pool}
+
+	// record the connection pool stats
+	monitoring.RegisterSqlStats(opt.statsName(), db.DB.DB.DB)
+
+	// store connection into hashMap
+	pgxMap[opt.dsn] = db
+	logger.GreenItalic("postgresql connected!")
+	return &sqlxInstance{db: db}, nil
 }
 
 // SetSqlDSN sets the database dsn
@@ -122,3 +302,52 @@ func SetSqlMaxConnection(maxConnection int) SqlFuncOption {
 		so.maxConnection = maxConnection
 	}
 }
+
+// SetSqlDriver sets the driver of the connection, e.g.: DriverPq, DriverPgx
+func SetSqlDriver(driver SqlDriver) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.driver = driver
+	}
+}
+
+// SetSqlStatementCacheCapacity sets the prepared statement cache capacity per connection, only for DriverPgx
+func SetSqlStatementCacheCapacity(capacity int) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.statementCacheCapacity = capacity
+	}
+}
+
+// SetSqlName sets the name of the database in the connection pool stats metrics, the name must be distinct for every connection
+func SetSqlName(name string) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.name = name
+	}
+}
+
+// SetSqlReplicas sets the dsn of the replicas, the database is dbc.Router which routes the reads to the replicas
+// and the writes or transactions to master, e.g.:
+//
+//	database.NewSqlxConnection(
+//		database.SetSqlDSN(env.GetString("DSN_MASTER")),
+//		database.SetSqlReplicas([]string{env.GetString("DSN_SLAVE")}, dbc.RouterOptionHealthCheck(5*time.Second)),
+//	)
+func SetSqlReplicas(dsns []string, opts ...dbc.RouterOptionFunc) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.replicaDsns = dsns
+		so.routerOptions = opts
+	}
+}
+
+// SetSqlSlowQueryThreshold sets the duration of slow query which is logged with warn level, 0 disable the slow query log
+func SetSqlSlowQueryThreshold(threshold time.Duration) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.slowQueryThreshold = threshold
+	}
+}
+
+// SetSqlExplainSlowQuery include the EXPLAIN plan in the slow query log
+func SetSqlExplainSlowQuery(explain bool) SqlFuncOption {
+	return func(so *sqlOption) {
+		so.explainSlowQuery = explain
+	}
+}
@@ -5,65 +5,33 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/mqdvi-dp/go-common/logger"
-	"github.com/mqdvi-dp/go-common/tracer"
-	"github.com/mqdvi-dp/go-common/zone"
+	"github.com/redis/go-redis/v9"
 )
 
 func (d *Db) Get(ctx context.Context, key string) (string, error) {
-	log := logger.DB(logger.Redis, "mget", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Get")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-
 	resp, err := d.DB.Get(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return "", err
 	}
 
-	// log result
-	trace.Log("result", resp)
-
 	return resp, nil
 }
 
 func (d *Db) GetStruct(ctx context.Context, dest interface{}, key string) error {
-	log := logger.DB(logger.Redis, "mget", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:GetJSON")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
 	// make sure, the destination is a pointer
 	err := validate(dest)
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
-	// log tracer
-	trace.Log("key", key)
-
 	// get the value
 	result, err := d.DB.Get(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
-	// log result
-	trace.Log("result", result)
-
 	err = json.Unmarshal([]byte(result), dest)
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
@@ -71,78 +39,31 @@ func (d *Db) GetStruct(ctx context.Context, dest interface{}, key string) error
 }
 
 func (d *Db) Incr(ctx context.Context, key string) (int64, error) {
-	log := logger.DB(logger.Redis, "incr", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Incr")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-
 	result, err := d.DB.Incr(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return -1, err
 	}
 
-	// log value
-	trace.Log("result", result)
-
 	return result, nil
 }
 
 func (d *Db) Decr(ctx context.Context, key string) (int64, error) {
-	log := logger.DB(logger.Redis, "decr", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Decr")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-
 	result, err := d.DB.Decr(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return -1, err
 	}
 
-	// log value
-	trace.Log("result", result)
-
 	return result, nil
 }
 
-func (d *Db) Set(ctx context.Context, key string, value interface{}, durations ...time.Duration) error {
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Set")
-
-	var expired time.Duration
-	if len(durations) > 0 {
-		expired = durations[0]
-	} else {
-		// default duration is until end of day
-		now := time.Now().In(zone.TzJakarta())
-		nd := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()) // get the next day
-		expired = nd.Sub(now)
+func (d *Db) Set(ctx context.Context, key string, value interface{}, opts ...WriteOption) error {
+	expired, keep := d.expiration(opts...)
+	if keep {
+		expired = redis.KeepTTL
 	}
 
-	log := logger.DB(logger.Redis, "set", key, value, expired)
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-	trace.Log("value", value)
-	trace.Log("expired_duration", expired)
-
 	err := d.DB.Set(ctx, key, value, expired).Err()
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
@@ -150,19 +71,13 @@ func (d *Db) Set(ctx context.Context, key string, value interface{}, durations .
 }
 
 func (d *Db) Del(ctx context.Context, keys ...string) error {
-	log := logger.DB(logger.Redis, "del", keys)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Del")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("keys", keys)
-
-	err := d.DB.Del(ctx, keys...).Err()
+	_, err := d.DB.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, group := range d.groupBySlot(keys) {
+			pipe.Del(ctx, group...)
+		}
+		return nil
+	})
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
@@ -170,18 +85,8 @@ func (d *Db) Del(ctx context.Context, keys ...string) error {
 }
 
 func (d *Db) TTL(ctx context.Context, key string) (time.Duration, error) {
-	log := logger.DB(logger.Redis, "ttl", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:TTL")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	trace.Log("key", key)
-
 	result, err := d.DB.TTL(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return -1, err
 	}
 
@@ -189,19 +94,8 @@ func (d *Db) TTL(ctx context.Context, key string) (time.Duration, error) {
 }
 
 func (d *Db) Expire(ctx context.Context, key string, duration time.Duration) error {
-	log := logger.DB(logger.Redis, "expire", key, duration)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Expire")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	trace.Log("key", key)
-	trace.Log("expired_duration", duration)
-
 	err := d.DB.Expire(ctx, key, duration).Err()
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
@@ -209,18 +103,13 @@ func (d *Db) Expire(ctx context.Context, key string, duration time.Duration) err
 }
 
 func (d *Db) Keys(ctx context.Context, patternKey string) ([]string, error) {
-	log := logger.DB(logger.Redis, "keys", patternKey)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:Keys")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	trace.Log("key", patternKey)
-
-	result, err := d.DB.Keys(ctx, patternKey).Result()
-	if err != nil {
-		trace.SetError(err)
+	// SCAN instead of KEYS, KEYS blocks redis until all keys are matched
+	var result []string
+	iter := d.ScanKeys(ctx, patternKey)
+	for iter.Next(ctx) {
+		result = append(result, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
 		return nil, err
 	}
 
@@ -228,23 +117,26 @@ func (d *Db) Keys(ctx context.Context, patternKey string) ([]string, error) {
 }
 
 func (d *Db) GetKeysAndDelete(ctx context.Context, patternKey string) (err error) {
-	log := logger.DB(logger.Redis, "keys and del", patternKey)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:GetKeysAndDelete")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	trace.Log("key", patternKey)
-	result, err := d.DB.Keys(ctx, patternKey).Result()
-	if err != nil {
-		trace.SetError(err)
+	// delete the keys in batches while scanning
+	size := scanCount()
+	batch := make([]string, 0, size)
+	iter := d.ScanKeys(ctx, patternKey)
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if int64(len(batch)) < size {
+			continue
+		}
+
+		if err = d.unlink(ctx, batch...); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if err = iter.Err(); err != nil {
 		return err
 	}
 
-	err = d.DB.Del(ctx, result...).Err()
-	if err != nil {
-		trace.SetError(err)
+	if err = d.unlink(ctx, batch...); err != nil {
 		return err
 	}
 
@@ -252,85 +144,27 @@ func (d *Db) GetKeysAndDelete(ctx context.Context, patternKey string) (err error
 }
 
 func (d *Db) DoSMembers(ctx context.Context, key string) (members []string, err error) {
-	log := logger.DB(logger.Redis, "do smembers", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:DoSMembers")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-
-	members, err = d.DB.SMembers(context.Background(), key).Result()
-	if err != nil {
-		trace.SetError(err)
-		return
-	}
-
-	// log result
-	trace.Log("result", members)
-
-	return
+	return d.DB.SMembers(ctx, key).Result()
 }
 
 func (d *Db) DoSIsMember(ctx context.Context, key, member string) (exist bool) {
-	log := logger.DB(logger.Redis, "do sismember", key, member)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:DoSIsMember")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-	trace.Log("member", member)
-
-	exist, err := d.DB.SIsMember(context.Background(), key, member).Result()
-	if err != nil {
-		trace.SetError(err)
-		return
-	}
-
-	// log result
-	trace.Log("result", exist)
-
-	return
+	return d.DB.SIsMember(ctx, key, member).Val()
 }
 
-func (d *Db) DoSadd(ctx context.Context, key string, value []string, durations ...time.Duration) (err error) {
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:DoSadd")
-
-	var expired time.Duration
-	if len(durations) > 0 {
-		expired = durations[0]
-	} else {
-		// default duration is until end of day
-		now := time.Now().In(zone.TzJakarta())
-		nd := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()) // get the next day
-		expired = nd.Sub(now)
-	}
-
-	log := logger.DB(logger.Redis, "DoSadd", key, value, expired)
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-	trace.Log("value", value)
-	trace.Log("expired_duration", expired)
+func (d *Db) DoSadd(ctx context.Context, key string, value []string, opts ...WriteOption) (err error) {
+	// 0 is not expired or the existing ttl is kept
+	expired, _ := d.expiration(opts...)
 
 	for i := 0; i < len(value); i++ {
 		err = d.DB.SAdd(ctx, key, value[i]).Err()
 		if err != nil {
-			trace.SetError(err)
 			return
 		}
+		if expired < 1 {
+			continue
+		}
 		err = d.DB.Expire(ctx, key, expired).Err()
 		if err != nil {
-			trace.SetError(err)
 			return
 		}
 	}
@@ -338,40 +172,20 @@ func (d *Db) DoSadd(ctx context.Context, key string, value []string, durations .
 	return
 }
 
-func (d *Db) HSet(ctx context.Context, key string, field string, value interface{}, durations ...time.Duration) error {
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:HSet")
-
-	var expired time.Duration
-	if len(durations) > 0 {
-		expired = durations[0]
-	} else {
-		// default duration is until end of day
-		now := time.Now().In(zone.TzJakarta())
-		nd := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()) // get the next day
-		expired = nd.Sub(now)
-	}
-
-	log := logger.DB(logger.Redis, "hset", key, value)
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-	trace.Log("field", field)
-	trace.Log("value", value)
-	trace.Log("expired_duration", expired)
+func (d *Db) HSet(ctx context.Context, key string, field string, value interface{}, opts ...WriteOption) error {
+	// 0 is not expired or the existing ttl is kept
+	expired, _ := d.expiration(opts...)
 
 	err := d.DB.HSet(ctx, key, field, value).Err()
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
+	if expired < 1 {
+		return nil
+	}
 
 	err = d.DB.Expire(ctx, key, expired).Err()
 	if err != nil {
-		trace.SetError(err)
 		return err
 	}
 
@@ -379,26 +193,11 @@ func (d *Db) HSet(ctx context.Context, key string, field string, value interface
 }
 
 func (d *Db) HGet(ctx context.Context, key string, field string) (string, error) {
-	log := logger.DB(logger.Redis, "HGet", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:HGet")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-	trace.Log("field", field)
-
 	resp, err := d.DB.HGet(ctx, key, field).Result()
 	if err != nil {
-		trace.SetError(err)
 		return "", err
 	}
 
-	// log result
-	trace.Log("result", resp)
-
 	return resp, nil
 }
 
@@ -411,24 +210,57 @@ func (d *Db) Ping(ctx context.Context) error {
 }
 
 func (d *Db) HGetAll(ctx context.Context, key string) (map[string]string, error) {
-	log := logger.DB(logger.Redis, "HGet", key)
-	trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:HGetAll")
-	defer func() {
-		log.Store(ctx)
-		trace.Finish()
-	}()
-
-	// log tracer
-	trace.Log("key", key)
-
 	resp, err := d.DB.HGetAll(ctx, key).Result()
 	if err != nil {
-		trace.SetError(err)
 		return nil, err
 	}
 
-	// log result
-	trace.Log("result", resp)
-
 	return resp, nil
 }
+
+func (d *Db) SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error) {
+	ok, err := d.DB.SetNX(ctx, key, value, duration).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (d *Db) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	err := d.DB.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *Db) ZRem(ctx context.Context, key string, members ...string) (int64, error) {
+	args := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+
+	result, err := d.DB.ZRem(ctx, key, args...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) ([]string, error) {
+	opt := &redis.ZRangeBy{Min: min, Max: max}
+	if count > 0 {
+		opt.Offset = offset
+		opt.Count = count
+	}
+
+	result, err := d.DB.ZRangeByScore(ctx, key, opt).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package rdc
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (d *Db) HMSet(ctx context.Context, key string, values map[string]interface{}, opts ...WriteOption) error {
+	// 0 is not expired or the existing ttl is kept
+	expired, _ := d.expiration(opts...)
+
+	_, err := d.DB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, values)
+		if expired > 0 {
+			pipe.Expire(ctx, key, expired)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *Db) HMGet(ctx context.Context, key string, fields ...string) (map[string]string, error) {
+	values, err := d.DB.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// the field does not exist is not returned
+	result := make(map[string]string, len(fields))
+	for i, val := range values {
+		if s, ok := val.(string); ok {
+			result[fields[i]] = s
+		}
+	}
+
+	return result, nil
+}
+
+func (d *Db) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	result, err := d.DB.HIncrBy(ctx, key, field, incr).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	result, err := d.DB.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
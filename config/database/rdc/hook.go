@@ -0,0 +1,127 @@
+package rdc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+// hook instruments every command of the client with the tracer, the database log and the latency metrics
+type hook struct{}
+
+// NewHook returns go-redis hook of the tracer, the database log and the latency metrics
+// for the client which is not created by New, e.g.:
+//
+//	client.AddHook(rdc.NewHook())
+func NewHook() redis.Hook {
+	return hook{}
+}
+
+// New create Db with the hook added into the client, call it once per client when the client is built
+func New(client redis.UniversalClient) *Db {
+	client.AddHook(NewHook())
+	return &Db{DB: client}
+}
+
+func (hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+		monitoring.RecordRedis("dial", err, time.Since(start))
+
+		return conn, err
+	}
+}
+
+func (hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		log := logger.DBContext(ctx, logger.Redis, cmd.FullName(), arguments(cmd)...)
+		trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:"+cmd.FullName())
+		defer func() {
+			log.Store(ctx)
+			trace.Finish()
+		}()
+
+		// log tracer
+		trace.Log("arguments", log.Arguments)
+
+		err := next(ctx, cmd)
+		monitoring.RecordRedis(cmd.Name(), err, time.Since(start))
+		if err != nil && !errors.Is(err, redis.Nil) {
+			trace.SetError(err)
+			return err
+		}
+
+		// log result
+		trace.Log("result", cmdResult(cmd))
+
+		return err
+	}
+}
+
+func (hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		// the commands of the transaction are wrapped with MULTI and EXEC
+		prefix := "pipeline"
+		if len(cmds) > 0 && cmds[0].Name() == "multi" {
+			prefix = "multi"
+		}
+
+		start := time.Now()
+		trace, ctx := tracer.StartTraceWithContext(ctx, "Rdc:"+prefix)
+		defer trace.Finish()
+
+		// log tracer
+		trace.Log("commands", len(cmds))
+
+		err := next(ctx, cmds)
+		monitoring.RecordRedis(prefix, err, time.Since(start))
+
+		// log every command of the pipeline or transaction
+		for _, cmd := range cmds {
+			if name := cmd.Name(); name == "multi" || name == "exec" {
+				continue
+			}
+
+			log := logger.DBContext(ctx, logger.Redis, prefix+" "+cmd.FullName(), arguments(cmd)...)
+			log.StartAt(start)
+			log.Store(ctx)
+		}
+
+		if err != nil && !errors.Is(err, redis.Nil) {
+			trace.SetError(err)
+		}
+
+		return err
+	}
+}
+
+// arguments returns the arguments of the command without the command name, the first argument is the key
+func arguments(cmd redis.Cmder) []interface{} {
+	args := cmd.Args()
+	// the sub command is the part of the name (e.g.: xgroup create)
+	if n := strings.Count(cmd.FullName(), " ") + 1; len(args) > n {
+		return args[n:]
+	}
+
+	return nil
+}
+
+// cmdResult returns the value of the command, every command of go-redis has Val method
+func cmdResult(cmd redis.Cmder) interface{} {
+	val := reflect.ValueOf(cmd).MethodByName("Val")
+	if !val.IsValid() || val.Type().NumIn() != 0 || val.Type().NumOut() != 1 {
+		return nil
+	}
+
+	return val.Call(nil)[0].Interface()
+}
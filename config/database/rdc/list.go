@@ -0,0 +1,92 @@
+package rdc
+
+import (
+	"context"
+	"time"
+)
+
+func (d *Db) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	// returns the length of the list
+	result, err := d.DB.LPush(ctx, key, values...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	// returns the length of the list
+	result, err := d.DB.RPush(ctx, key, values...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) LPop(ctx context.Context, key string) (string, error) {
+	// redis.Nil when the list is empty
+	resp, err := d.DB.LPop(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (d *Db) RPop(ctx context.Context, key string) (string, error) {
+	// redis.Nil when the list is empty
+	resp, err := d.DB.RPop(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (d *Db) BLPop(ctx context.Context, timeout time.Duration, keys ...string) (key string, value string, err error) {
+	// redis.Nil when the timeout exceeded, the result is [key, value]
+	resp, err := d.DB.BLPop(ctx, timeout, keys...).Result()
+	if err != nil {
+		return "", "", err
+	}
+
+	return resp[0], resp[1], nil
+}
+
+func (d *Db) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	result, err := d.DB.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) LLen(ctx context.Context, key string) (int64, error) {
+	result, err := d.DB.LLen(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) LTrim(ctx context.Context, key string, start, stop int64) error {
+	err := d.DB.LTrim(ctx, key, start, stop).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *Db) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	result, err := d.DB.LRem(ctx, key, count, value).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
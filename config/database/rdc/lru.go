@@ -0,0 +1,129 @@
+package rdc
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is size and ttl bounded in-process cache
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+	// generation is increased on every invalidation, the value loaded before the invalidation is not stored
+	generation uint64
+}
+
+type lruEntry struct {
+	key    string
+	value  interface{}
+	expiry time.Time
+}
+
+func newLru(size int, ttl time.Duration) *lru {
+	return &lru{size: size, ttl: ttl, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns the value of the key, the expired value is removed
+func (l *lru) get(key string) (interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiry) {
+		l.remove(el)
+		return nil, false
+	}
+
+	l.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+// gen returns the current generation, it must be taken before loading the value
+func (l *lru) gen() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.generation
+}
+
+// add store the value when there is no invalidation since gen, returns the number of evicted entries
+func (l *lru) add(gen uint64, key string, value interface{}) (evicted int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if gen != l.generation {
+		return 0
+	}
+
+	expiry := time.Now().Add(l.ttl)
+	if el, ok := l.items[key]; ok {
+		el.Value = &lruEntry{key: key, value: value, expiry: expiry}
+		l.ll.MoveToFront(el)
+		return 0
+	}
+
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: value, expiry: expiry})
+	for l.size > 0 && l.ll.Len() > l.size {
+		l.remove(l.ll.Back())
+		evicted++
+	}
+
+	return evicted
+}
+
+// invalidate remove the keys
+func (l *lru) invalidate(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	for _, key := range keys {
+		if el, ok := l.items[key]; ok {
+			l.remove(el)
+		}
+	}
+}
+
+// invalidateFunc remove the keys matched by fn
+func (l *lru) invalidateFunc(fn func(key string) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	for key, el := range l.items {
+		if fn(key) {
+			l.remove(el)
+		}
+	}
+}
+
+// purge remove all keys
+func (l *lru) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	l.ll.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+func (l *lru) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.ll.Len()
+}
+
+func (l *lru) remove(el *list.Element) {
+	l.ll.Remove(el)
+	delete(l.items, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,590 @@
+package rdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	nearString = "s:"
+	nearHash   = "h:"
+)
+
+type nearCacheOption struct {
+	name     string
+	prefixes []string
+	size     int
+	ttl      time.Duration
+	channel  string
+}
+
+// NearCacheOptionFunc option func for near cache
+type NearCacheOptionFunc func(*nearCacheOption)
+
+func getDefaultNearCacheOption() nearCacheOption {
+	return nearCacheOption{
+		name:     "near_cache",
+		prefixes: env.GetListString("REDIS_NEAR_CACHE_PREFIXES"),
+		size:     env.GetInt("REDIS_NEAR_CACHE_SIZE", 10000),
+		ttl:      env.GetDuration("REDIS_NEAR_CACHE_TTL", time.Minute),
+		channel:  env.GetString("REDIS_NEAR_CACHE_CHANNEL", "rdc:near_cache:invalidate"),
+	}
+}
+
+// NearCacheOptionName set the name of near cache, used as label of the metrics
+func NearCacheOptionName(name string) NearCacheOptionFunc {
+	return func(o *nearCacheOption) {
+		o.name = name
+	}
+}
+
+// NearCacheOptionPrefixes set the key prefixes cached in the process, the other keys always read from redis
+func NearCacheOptionPrefixes(prefixes ...string) NearCacheOptionFunc {
+	return func(o *nearCacheOption) {
+		o.prefixes = prefixes
+	}
+}
+
+// NearCacheOptionSize set the maximum number of keys cached in the process
+func NearCacheOptionSize(size int) NearCacheOptionFunc {
+	return func(o *nearCacheOption) {
+		o.size = size
+	}
+}
+
+// NearCacheOptionTTL set the maximum duration of the key cached in the process,
+// it bounds the staleness when the invalidation message is lost
+func NearCacheOptionTTL(ttl time.Duration) NearCacheOptionFunc {
+	return func(o *nearCacheOption) {
+		o.ttl = ttl
+	}
+}
+
+// NearCacheOptionChannel set the pub/sub channel of the invalidation messages
+func NearCacheOptionChannel(channel string) NearCacheOptionFunc {
+	return func(o *nearCacheOption) {
+		o.channel = channel
+	}
+}
+
+// nearMessage is the invalidation message published into the other instances
+type nearMessage struct {
+	Id      string   `json:"id"`
+	Keys    []string `json:"keys,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// NearCache is in-process LRU in front of redis for read-heavy keys (e.g.: maintenance window, product catalog).
+// the writes through NearCache publish the invalidation message, so the other instances drop the stale keys.
+// only Get, GetStruct and HGetAll of the opted-in prefixes are cached, the other commands are passed into redis.
+//
+// the keys of the pipelines and the scripts are invalidated after the call, Watch invalidates the watched keys only.
+// the commands which only remove from list, set, sorted set or stream are not invalidated,
+// since they can not change the cached string or hash.
+// the keys changed outside of NearCache (e.g.: the other services, the commands of Watch on the unwatched keys)
+// must be invalidated with Invalidate, otherwise they are stale until the TTL of the near cache
+type NearCache struct {
+	Rdc
+
+	id     string
+	opt    nearCacheOption
+	client redis.UniversalClient
+	local  *lru
+	cancel context.CancelFunc
+}
+
+// NewNearCache create near cache on top of db and subscribe the invalidation messages,
+// see NearCache for the keys which must be invalidated manually, e.g.:
+//
+//	client := rdc.NewNearCache(db, rdc.NearCacheOptionPrefixes(constants.PrefixMaintenanceWindow, "product:"))
+func NewNearCache(db *Db, opts ...NearCacheOptionFunc) *NearCache {
+	opt := getDefaultNearCacheOption()
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	// ignore the empty prefix, it matches all keys
+	prefixes := make([]string, 0, len(opt.prefixes))
+	for _, prefix := range opt.prefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	opt.prefixes = prefixes
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &NearCache{
+		Rdc:    db,
+		id:     uuid.NewString(),
+		opt:    opt,
+		client: db.DB,
+		local:  newLru(opt.size, opt.ttl),
+		cancel: cancel,
+	}
+
+	if len(n.opt.prefixes) > 0 {
+		go n.subscribe(ctx)
+	}
+
+	return n
+}
+
+func (n *NearCache) Get(ctx context.Context, key string) (string, error) {
+	if !n.cacheable(key) {
+		return n.Rdc.Get(ctx, key)
+	}
+
+	if val, ok := n.local.get(nearString + key); ok {
+		monitoring.RecordCache(n.opt.name, "hit")
+		if err, ok := val.(error); ok {
+			return "", err
+		}
+		return val.(string), nil
+	}
+	monitoring.RecordCache(n.opt.name, "miss")
+
+	gen := n.local.gen()
+	resp, err := n.Rdc.Get(ctx, key)
+	switch {
+	case err == nil:
+		n.add(gen, nearString+key, resp)
+	case errors.Is(err, redis.Nil):
+		// the key is not exists, e.g.: no maintenance window
+		n.add(gen, nearString+key, redis.Nil)
+	}
+
+	return resp, err
+}
+
+func (n *NearCache) GetStruct(ctx context.Context, dest interface{}, key string) error {
+	if !n.cacheable(key) {
+		return n.Rdc.GetStruct(ctx, dest, key)
+	}
+
+	// make sure, the destination is a pointer
+	if err := validate(dest); err != nil {
+		return err
+	}
+
+	result, err := n.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(result), dest)
+}
+
+func (n *NearCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if !n.cacheable(key) {
+		return n.Rdc.HGetAll(ctx, key)
+	}
+
+	if val, ok := n.local.get(nearHash + key); ok {
+		monitoring.RecordCache(n.opt.name, "hit")
+		return copyHash(val.(map[string]string)), nil
+	}
+	monitoring.RecordCache(n.opt.name, "miss")
+
+	gen := n.local.gen()
+	resp, err := n.Rdc.HGetAll(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	n.add(gen, nearHash+key, copyHash(resp))
+	return resp, nil
+}
+
+func (n *NearCache) Incr(ctx context.Context, key string) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.Incr(ctx, key)
+}
+
+func (n *NearCache) Decr(ctx context.Context, key string) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.Decr(ctx, key)
+}
+
+func (n *NearCache) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.IncrBy(ctx, key, value)
+}
+
+func (n *NearCache) GetSet(ctx context.Context, key string, value interface{}) (string, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.GetSet(ctx, key, value)
+}
+
+func (n *NearCache) GetDel(ctx context.Context, key string) (string, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.GetDel(ctx, key)
+}
+
+func (n *NearCache) Set(ctx context.Context, key string, value interface{}, opts ...WriteOption) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.Set(ctx, key, value, opts...)
+}
+
+func (n *NearCache) MSet(ctx context.Context, values map[string]interface{}, opts ...WriteOption) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	defer n.invalidate(ctx, keys...)
+	return n.Rdc.MSet(ctx, values, opts...)
+}
+
+func (n *NearCache) SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error) {
+	ok, err := n.Rdc.SetNX(ctx, key, value, duration)
+	if ok {
+		// the key may be cached as not exists
+		n.invalidate(ctx, key)
+	}
+
+	return ok, err
+}
+
+func (n *NearCache) HSet(ctx context.Context, key string, field string, value interface{}, opts ...WriteOption) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.HSet(ctx, key, field, value, opts...)
+}
+
+func (n *NearCache) HMSet(ctx context.Context, key string, values map[string]interface{}, opts ...WriteOption) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.HMSet(ctx, key, values, opts...)
+}
+
+func (n *NearCache) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.HIncrBy(ctx, key, field, incr)
+}
+
+func (n *NearCache) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.HDel(ctx, key, fields...)
+}
+
+func (n *NearCache) Del(ctx context.Context, keys ...string) error {
+	defer n.invalidate(ctx, keys...)
+	return n.Rdc.Del(ctx, keys...)
+}
+
+func (n *NearCache) Expire(ctx context.Context, key string, duration time.Duration) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.Expire(ctx, key, duration)
+}
+
+func (n *NearCache) DoSadd(ctx context.Context, key string, value []string, opts ...WriteOption) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.DoSadd(ctx, key, value, opts...)
+}
+
+func (n *NearCache) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.ZAdd(ctx, key, score, member)
+}
+
+func (n *NearCache) ZIncrBy(ctx context.Context, key string, incr float64, member string) (float64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.ZIncrBy(ctx, key, incr, member)
+}
+
+func (n *NearCache) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.LPush(ctx, key, values...)
+}
+
+func (n *NearCache) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.RPush(ctx, key, values...)
+}
+
+func (n *NearCache) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	defer n.invalidate(ctx, stream)
+	return n.Rdc.XAdd(ctx, stream, values, maxLen)
+}
+
+func (n *NearCache) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	defer n.invalidate(ctx, stream)
+	return n.Rdc.XGroupCreate(ctx, stream, group, start)
+}
+
+func (n *NearCache) SetBit(ctx context.Context, key string, offset int64, value int) (int64, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.SetBit(ctx, key, offset, value)
+}
+
+func (n *NearCache) PFAdd(ctx context.Context, key string, elements ...interface{}) (bool, error) {
+	defer n.invalidate(ctx, key)
+	return n.Rdc.PFAdd(ctx, key, elements...)
+}
+
+func (n *NearCache) PFMerge(ctx context.Context, dest string, keys ...string) error {
+	defer n.invalidate(ctx, dest)
+	return n.Rdc.PFMerge(ctx, dest, keys...)
+}
+
+func (n *NearCache) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	cmds, err := n.Rdc.Pipelined(ctx, fn)
+	n.invalidate(ctx, writtenKeys(cmds)...)
+
+	return cmds, err
+}
+
+func (n *NearCache) TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	cmds, err := n.Rdc.TxPipelined(ctx, fn)
+	n.invalidate(ctx, writtenKeys(cmds)...)
+
+	return cmds, err
+}
+
+func (n *NearCache) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error {
+	defer n.invalidate(ctx, keys...)
+	return n.Rdc.Watch(ctx, fn, keys...)
+}
+
+func (n *NearCache) EvalScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	defer n.invalidate(ctx, keys...)
+	return n.Rdc.EvalScript(ctx, script, keys, args...)
+}
+
+func (n *NearCache) GetKeysAndDelete(ctx context.Context, patternKey string) error {
+	defer n.invalidatePattern(ctx, patternKey)
+	return n.Rdc.GetKeysAndDelete(ctx, patternKey)
+}
+
+// Invalidate drop the keys from the near cache of all instances, used when the keys are changed outside of NearCache
+func (n *NearCache) Invalidate(ctx context.Context, keys ...string) {
+	n.invalidate(ctx, keys...)
+}
+
+// Close stop the subscription and close the connection
+func (n *NearCache) Close() error {
+	n.cancel()
+	return n.Rdc.Close()
+}
+
+// cacheable returns true when the key is opted-in by the prefixes
+func (n *NearCache) cacheable(key string) bool {
+	for _, prefix := range n.opt.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *NearCache) add(gen uint64, key string, value interface{}) {
+	for i := n.local.add(gen, key, value); i > 0; i-- {
+		monitoring.RecordCache(n.opt.name, "evict")
+	}
+}
+
+// invalidate drop the cached keys and publish the invalidation message
+func (n *NearCache) invalidate(ctx context.Context, keys ...string) {
+	var cached []string
+	for _, key := range keys {
+		if n.cacheable(key) {
+			cached = append(cached, key)
+		}
+	}
+	if len(cached) < 1 {
+		return
+	}
+
+	n.drop(cached...)
+	n.publish(ctx, nearMessage{Id: n.id, Keys: cached})
+}
+
+// invalidatePattern drop the cached keys matched with the pattern and publish the invalidation message
+func (n *NearCache) invalidatePattern(ctx context.Context, pattern string) {
+	if len(n.opt.prefixes) < 1 {
+		return
+	}
+
+	n.dropPattern(pattern)
+	n.publish(ctx, nearMessage{Id: n.id, Pattern: pattern})
+}
+
+func (n *NearCache) drop(keys ...string) {
+	locals := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		locals = append(locals, nearString+key, nearHash+key)
+	}
+
+	n.local.invalidate(locals...)
+	monitoring.RecordCache(n.opt.name, "invalidate")
+}
+
+func (n *NearCache) dropPattern(pattern string) {
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		// unknown pattern, drop all keys
+		n.local.purge()
+		monitoring.RecordCache(n.opt.name, "invalidate")
+		return
+	}
+
+	n.local.invalidateFunc(func(key string) bool {
+		return re.MatchString(key[len(nearString):])
+	})
+	monitoring.RecordCache(n.opt.name, "invalidate")
+}
+
+func (n *NearCache) publish(ctx context.Context, msg nearMessage) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	if err = n.client.Publish(context.WithoutCancel(ctx), n.opt.channel, payload).Err(); err != nil {
+		logger.Red(fmt.Sprintf("near cache > failed to publish invalidation of %s: %s", n.opt.channel, err))
+	}
+}
+
+// subscribe receive the invalidation messages of the other instances until ctx is canceled.
+// the near cache is purged when the subscription is (re)established, since the messages may be lost
+func (n *NearCache) subscribe(ctx context.Context) {
+	pubsub := n.client.Subscribe(ctx, n.opt.channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			n.local.purge()
+			logger.Red(fmt.Sprintf("near cache > failed to receive invalidation of %s: %s", n.opt.channel, err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			n.local.purge()
+		case *redis.Message:
+			var message nearMessage
+			if err = json.Unmarshal([]byte(m.Payload), &message); err != nil || message.Id == n.id {
+				continue
+			}
+
+			if len(message.Keys) > 0 {
+				n.drop(message.Keys...)
+			}
+			if message.Pattern != "" {
+				n.dropPattern(message.Pattern)
+			}
+		}
+	}
+}
+
+// globToRegexp convert the glob pattern of redis (e.g.: product:*) into regexp
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid pattern %s", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + strings.ReplaceAll(class[1:], `\`, `\\`)
+			} else {
+				class = strings.ReplaceAll(class, `\`, `\\`)
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// readCommands is the commands which do not change the key, they are not invalidated in the pipelines
+var readCommands = map[string]bool{
+	"get": true, "mget": true, "strlen": true, "getrange": true, "getbit": true, "bitcount": true,
+	"hget": true, "hmget": true, "hgetall": true, "hexists": true, "hlen": true, "hkeys": true, "hvals": true,
+	"exists": true, "ttl": true, "pttl": true, "type": true,
+}
+
+// writtenKeys returns the keys of the commands which may change the key
+func writtenKeys(cmds []redis.Cmder) []string {
+	var keys []string
+	for _, cmd := range cmds {
+		args := cmd.Args()
+		if len(args) < 2 || readCommands[cmd.Name()] {
+			continue
+		}
+
+		var pos []interface{}
+		switch cmd.Name() {
+		case "del", "unlink":
+			pos = args[1:]
+		case "mset", "msetnx":
+			for i := 1; i < len(args); i += 2 {
+				pos = append(pos, args[i])
+			}
+		case "eval", "evalsha", "eval_ro", "evalsha_ro":
+			// EVAL script numkeys key [key ...] arg [arg ...]
+			if len(args) > 2 {
+				if n, ok := args[2].(int); ok && 3+n <= len(args) {
+					pos = args[3 : 3+n]
+				}
+			}
+		default:
+			pos = args[1:2]
+		}
+
+		for _, key := range pos {
+			if s, ok := key.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+	}
+
+	return keys
+}
+
+func copyHash(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+
+	return result
+}
@@ -0,0 +1,26 @@
+package rdc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrittenKeys(t *testing.T) {
+	ctx := context.Background()
+	cmds := []redis.Cmder{
+		redis.NewStatusCmd(ctx, "set", "a", "1"),
+		redis.NewStringCmd(ctx, "get", "b"),
+		redis.NewIntCmd(ctx, "hset", "c", "field", "1"),
+		redis.NewMapStringStringCmd(ctx, "hgetall", "d"),
+		redis.NewIntCmd(ctx, "del", "e", "f"),
+		redis.NewStatusCmd(ctx, "mset", "g", "1", "h", "2"),
+		redis.NewCmd(ctx, "evalsha", "sha", 2, "i", "j", "arg"),
+		redis.NewIntCmd(ctx, "LPUSH", "k", "1"),
+		redis.NewStatusCmd(ctx, "ping"),
+	}
+
+	assert.Equal(t, []string{"a", "c", "e", "f", "g", "h", "i", "j", "k"}, writtenKeys(cmds))
+}
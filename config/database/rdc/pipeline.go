@@ -0,0 +1,21 @@
+package rdc
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Pipelined, TxPipelined and Watch are traced and logged every command by the hook
+
+func (d *Db) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return d.DB.Pipelined(ctx, fn)
+}
+
+func (d *Db) TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return d.DB.TxPipelined(ctx, fn)
+}
+
+func (d *Db) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error {
+	return d.DB.Watch(ctx, fn, keys...)
+}
@@ -20,8 +20,15 @@ func validate(val interface{}) error {
 	return nil
 }
 
+// Db is the redis client, the commands are traced, logged and measured by the hook (see New and NewHook).
+//
+// the tracing and the logging moved from every method into the hook of the client, which is added by New.
+// Db created as &rdc.Db{DB: client} is not instrumented, create it with New or add NewHook into the client
 type Db struct {
 	DB redis.UniversalClient
+	// TTLPolicy is the expiration of the write commands when the ttl is not set on the call,
+	// the zero value is the end of the day in Asia/Jakarta
+	TTLPolicy TTLPolicy
 }
 
 type Rdc interface {
@@ -38,8 +45,8 @@ type Rdc interface {
 	Decr(ctx context.Context, key string) (int64, error)
 
 	// Set value into redis with data type string
-	// default of expired duration is until end of day
-	Set(ctx context.Context, key string, value interface{}, durations ...time.Duration) error
+	// default of expired duration is the ttl policy of the connection (end of day in Asia/Jakarta)
+	Set(ctx context.Context, key string, value interface{}, opts ...WriteOption) error
 
 	// Del value from redis
 	Del(ctx context.Context, keys ...string) error
@@ -50,12 +57,22 @@ type Rdc interface {
 	// Expire set the expired time of the key
 	Expire(ctx context.Context, key string, duration time.Duration) error
 
-	// Keys get all keys related that pattern
+	// Keys get all keys related that pattern with SCAN
 	Keys(ctx context.Context, patternKey string) ([]string, error)
 
-	// GetKeysAndDelete get all keys based on patterns and delete the keys after that
+	// ScanKeys returns iterator of the keys related that pattern, the keys of every master are scanned in the cluster mode
+	ScanKeys(ctx context.Context, patternKey string) *KeyIterator
+
+	// GetKeysAndDelete scan all keys based on patterns and unlink the keys in batches
 	GetKeysAndDelete(ctx context.Context, patternKey string) (err error)
 
+	// MGet returns the values of the existing keys, the keys are grouped by slot in the cluster mode
+	MGet(ctx context.Context, keys ...string) (map[string]string, error)
+
+	// MSet set the values with the same expired duration, the keys are grouped by slot in the cluster mode
+	// default of expired duration is the ttl policy of the connection (end of day in Asia/Jakarta)
+	MSet(ctx context.Context, values map[string]interface{}, opts ...WriteOption) error
+
 	// Returns all the members of the set value stored at key.
 	DoSMembers(ctx context.Context, key string) (members []string, err error)
 
@@ -63,11 +80,11 @@ type Rdc interface {
 	DoSIsMember(ctx context.Context, key, member string) (exist bool)
 
 	// Add the specified members with array of string data type to the set stored at key
-	DoSadd(ctx context.Context, key string, value []string, durations ...time.Duration) (err error)
+	DoSadd(ctx context.Context, key string, value []string, opts ...WriteOption) (err error)
 
 	// Set value with field
-	// default of expired duration is until end of day
-	HSet(ctx context.Context, key string, field string, value interface{}, durations ...time.Duration) error
+	// default of expired duration is the ttl policy of the connection (end of day in Asia/Jakarta)
+	HSet(ctx context.Context, key string, field string, value interface{}, opts ...WriteOption) error
 
 	// HGet returns single value from selected key and field
 	HGet(ctx context.Context, key string, field string) (string, error)
@@ -75,6 +92,162 @@ type Rdc interface {
 	// HGetAll returns all fields under selected key
 	HGetAll(ctx context.Context, key string) (map[string]string, error)
 
+	// SetNX set value into redis only when the key does not exist, returns false when the key already exists
+	SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error)
+
+	// ZAdd add member with score into sorted set, update the score when the member already exists
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+
+	// ZRem remove members from sorted set, returns the number of removed members
+	ZRem(ctx context.Context, key string, members ...string) (int64, error)
+
+	// ZRangeByScore returns members of sorted set with score between min and max (inclusive), e.g.: "-inf", "+inf", "(10"
+	// count less than 1 returns all members
+	ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) ([]string, error)
+
+	// IncrBy increase the value by the increment
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+
+	// GetSet set the value and returns the old value, redis.Nil when the key does not exist before
+	GetSet(ctx context.Context, key string, value interface{}) (string, error)
+
+	// GetDel returns the value and delete the key
+	GetDel(ctx context.Context, key string) (string, error)
+
+	// HMSet set multiple fields of hash
+	// default of expired duration is the ttl policy of the connection (end of day in Asia/Jakarta)
+	HMSet(ctx context.Context, key string, values map[string]interface{}, opts ...WriteOption) error
+
+	// HMGet returns the values of the existing fields
+	HMGet(ctx context.Context, key string, fields ...string) (map[string]string, error)
+
+	// HIncrBy increase the value of the hash field by the increment
+	HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error)
+
+	// HDel delete the fields of hash, returns the number of deleted fields
+	HDel(ctx context.Context, key string, fields ...string) (int64, error)
+
+	// ZIncrBy increase the score of the member
+	ZIncrBy(ctx context.Context, key string, incr float64, member string) (float64, error)
+
+	// ZScore returns the score of the member, redis.Nil when the member does not exist
+	ZScore(ctx context.Context, key, member string) (float64, error)
+
+	// ZRank returns the rank of the member by the score ascending, redis.Nil when the member does not exist
+	ZRank(ctx context.Context, key, member string) (int64, error)
+
+	// ZRevRank returns the rank of the member by the score descending, redis.Nil when the member does not exist
+	ZRevRank(ctx context.Context, key, member string) (int64, error)
+
+	// ZRange returns the members by the index ascending, e.g.: 0 and -1 returns all members
+	ZRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+
+	// ZRevRange returns the members by the index descending
+	ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+
+	// ZRangeWithScores returns the members and the scores by the index ascending
+	ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error)
+
+	// ZCard returns the number of members
+	ZCard(ctx context.Context, key string) (int64, error)
+
+	// ZCount returns the number of members with score between min and max
+	ZCount(ctx context.Context, key string, min, max string) (int64, error)
+
+	// ZRemRangeByScore remove the members with score between min and max
+	ZRemRangeByScore(ctx context.Context, key string, min, max string) (int64, error)
+
+	// LPush insert the values at the head of the list
+	LPush(ctx context.Context, key string, values ...interface{}) (int64, error)
+
+	// RPush insert the values at the tail of the list
+	RPush(ctx context.Context, key string, values ...interface{}) (int64, error)
+
+	// LPop remove and returns the first value of the list, redis.Nil when the list is empty
+	LPop(ctx context.Context, key string) (string, error)
+
+	// RPop remove and returns the last value of the list, redis.Nil when the list is empty
+	RPop(ctx context.Context, key string) (string, error)
+
+	// BLPop block until the value of the lists is available or timeout, returns the key and the value
+	BLPop(ctx context.Context, timeout time.Duration, keys ...string) (key string, value string, err error)
+
+	// LRange returns the values by the index, e.g.: 0 and -1 returns all values
+	LRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+
+	// LLen returns the length of the list
+	LLen(ctx context.Context, key string) (int64, error)
+
+	// LTrim keep the values between start and stop
+	LTrim(ctx context.Context, key string, start, stop int64) error
+
+	// LRem remove count occurrences of the value
+	LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error)
+
+	// XAdd append the message into the stream, maxLen greater than 0 trims the stream approximately
+	XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error)
+
+	// XRange returns the messages between start and stop id, count less than 1 returns all messages
+	XRange(ctx context.Context, stream, start, stop string, count int64) ([]redis.XMessage, error)
+
+	// XLen returns the number of messages of the stream
+	XLen(ctx context.Context, stream string) (int64, error)
+
+	// XDel delete the messages of the stream
+	XDel(ctx context.Context, stream string, ids ...string) (int64, error)
+
+	// XTrim trim the stream approximately into maxLen
+	XTrim(ctx context.Context, stream string, maxLen int64) (int64, error)
+
+	// XGroupCreate create the consumer group and the stream, start "0" consume from the first message and "$" only the new messages.
+	// no error when the group already exists
+	XGroupCreate(ctx context.Context, stream, group, start string) error
+
+	// XReadGroup read the new messages of the consumer group, block until the messages are available or timeout (redis.Nil)
+	XReadGroup(ctx context.Context, group, consumer, stream string, count int64, block time.Duration) ([]redis.XMessage, error)
+
+	// XAck acknowledge the messages, the messages are removed from the pending entries of the group
+	XAck(ctx context.Context, stream, group string, ids ...string) (int64, error)
+
+	// XAutoClaim claim the pending messages idle longer than minIdle into the consumer, returns the next start id
+	XAutoClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64) ([]redis.XMessage, string, error)
+
+	// XPending returns the pending entries (consumer, idle and delivery count) between start and end id
+	XPending(ctx context.Context, stream, group, start, end string, count int64) ([]redis.XPendingExt, error)
+
+	// SetBit set the bit at offset, returns the previous bit
+	SetBit(ctx context.Context, key string, offset int64, value int) (int64, error)
+
+	// GetBit returns the bit at offset
+	GetBit(ctx context.Context, key string, offset int64) (int64, error)
+
+	// BitCount returns the number of set bits
+	BitCount(ctx context.Context, key string) (int64, error)
+
+	// PFAdd add the elements into HyperLogLog, returns true when the estimated cardinality is changed
+	PFAdd(ctx context.Context, key string, elements ...interface{}) (bool, error)
+
+	// PFCount returns the estimated cardinality of the union of HyperLogLog
+	PFCount(ctx context.Context, keys ...string) (int64, error)
+
+	// PFMerge merge the HyperLogLog into dest
+	PFMerge(ctx context.Context, dest string, keys ...string) error
+
+	// Pipelined send the commands in a round trip, every command is logged
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+
+	// TxPipelined send the commands wrapped with MULTI/EXEC, every command is logged
+	TxPipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+
+	// Watch run the optimistic transaction, the transaction is failed with redis.TxFailedErr when the keys are changed
+	Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error
+
+	// LoadScripts load the lua scripts into redis (SCRIPT LOAD), so EvalScript does not send the script source
+	LoadScripts(ctx context.Context, scripts ...*redis.Script) error
+
+	// EvalScript run the lua script with EVALSHA, the script is loaded when not exists
+	EvalScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error)
+
 	// Close the connection
 	Close() error
 
@@ -0,0 +1,234 @@
+package rdc
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/redis/go-redis/v9"
+)
+
+// clusterSlots is the number of hash slots of redis cluster
+const clusterSlots = 16384
+
+// KeyIterator iterate the keys of SCAN, in the cluster mode the keys of every master are iterated, e.g.:
+//
+//	iter := client.ScanKeys(ctx, "otp:*")
+//	for iter.Next(ctx) {
+//		fmt.Println(iter.Val())
+//	}
+//	if err := iter.Err(); err != nil {
+//		return err
+//	}
+type KeyIterator struct {
+	nodes   []redis.Cmdable
+	pattern string
+	count   int64
+	iter    *redis.ScanIterator
+	err     error
+}
+
+// Next advance the iterator, returns false when all keys are iterated or on error
+func (it *KeyIterator) Next(ctx context.Context) bool {
+	for it.err == nil {
+		if it.iter == nil {
+			if len(it.nodes) < 1 {
+				return false
+			}
+
+			it.iter = it.nodes[0].Scan(ctx, 0, it.pattern, it.count).Iterator()
+			it.nodes = it.nodes[1:]
+		}
+
+		if it.iter.Next(ctx) {
+			return true
+		}
+
+		it.err = it.iter.Err()
+		it.iter = nil
+	}
+
+	return false
+}
+
+// Val returns the current key
+func (it *KeyIterator) Val() string {
+	if it.iter == nil {
+		return ""
+	}
+
+	return it.iter.Val()
+}
+
+// Err returns the error of the iteration
+func (it *KeyIterator) Err() error {
+	return it.err
+}
+
+func (d *Db) ScanKeys(ctx context.Context, patternKey string) *KeyIterator {
+	it := &KeyIterator{pattern: patternKey, count: scanCount()}
+
+	cluster, ok := d.DB.(*redis.ClusterClient)
+	if !ok {
+		it.nodes = []redis.Cmdable{d.DB}
+		return it
+	}
+
+	// SCAN only returns the keys of the node, so every master must be scanned
+	var mu sync.Mutex
+	it.err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		it.nodes = append(it.nodes, client)
+		return nil
+	})
+
+	return it
+}
+
+func (d *Db) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	if len(keys) < 1 {
+		return result, nil
+	}
+
+	groups := d.groupBySlot(keys)
+	cmds := make([]*redis.SliceCmd, 0, len(groups))
+	_, err := d.DB.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, group := range groups {
+			cmds = append(cmds, pipe.MGet(ctx, group...))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		for j, val := range cmd.Val() {
+			// nil is the key does not exist
+			if s, ok := val.(string); ok {
+				result[groups[i][j]] = s
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (d *Db) MSet(ctx context.Context, values map[string]interface{}, opts ...WriteOption) error {
+	expired, keep := d.expiration(opts...)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	if len(keys) < 1 {
+		return nil
+	}
+
+	_, err := d.DB.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, group := range d.groupBySlot(keys) {
+			// MSET remove the existing ttl, SET KEEPTTL every key instead
+			if keep {
+				for _, key := range group {
+					pipe.Set(ctx, key, values[key], redis.KeepTTL)
+				}
+				continue
+			}
+
+			pairs := make([]interface{}, 0, len(group)*2)
+			for _, key := range group {
+				pairs = append(pairs, key, values[key])
+			}
+
+			pipe.MSet(ctx, pairs...)
+			for _, key := range group {
+				if expired > 0 {
+					pipe.Expire(ctx, key, expired)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unlink delete the keys with UNLINK by the slot in a pipeline, the memory is reclaimed by redis in background
+func (d *Db) unlink(ctx context.Context, keys ...string) error {
+	if len(keys) < 1 {
+		return nil
+	}
+
+	_, err := d.DB.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, group := range d.groupBySlot(keys) {
+			pipe.Unlink(ctx, group...)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// groupBySlot group the keys by the hash slot in the cluster mode, so the multi-key commands do not fail with CROSSSLOT.
+// the keys are not grouped for the standalone and sentinel
+func (d *Db) groupBySlot(keys []string) [][]string {
+	if _, ok := d.DB.(*redis.ClusterClient); !ok {
+		return [][]string{keys}
+	}
+
+	var groups [][]string
+	index := make(map[int]int)
+	for _, key := range keys {
+		s := slot(key)
+		i, ok := index[s]
+		if !ok {
+			i = len(groups)
+			index[s] = i
+			groups = append(groups, nil)
+		}
+
+		groups[i] = append(groups[i], key)
+	}
+
+	return groups
+}
+
+// slot returns the hash slot of the key, the hash tag (e.g.: {user:1}:otp) is hashed when exists
+func slot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+
+	return int(crc16(key)) % clusterSlots
+}
+
+// crc16 is CRC16-CCITT (XMODEM) used by redis cluster
+func crc16(key string) uint16 {
+	var crc uint16
+	for i := 0; i < len(key); i++ {
+		crc ^= uint16(key[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// scanCount returns the COUNT of SCAN and the batch size of deletion
+func scanCount() int64 {
+	return env.GetInt64("REDIS_SCAN_COUNT", 500)
+}
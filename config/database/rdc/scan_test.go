@@ -0,0 +1,58 @@
+package rdc
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrc16(t *testing.T) {
+	// check value of CRC16-CCITT (XMODEM)
+	assert.Equal(t, uint16(0x31c3), crc16("123456789"))
+	assert.Equal(t, uint16(0), crc16(""))
+}
+
+func TestSlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int
+	}{
+		// CLUSTER KEYSLOT of redis
+		{key: "foo", want: 12182},
+		{key: "bar", want: 5061},
+		{key: "hello", want: 866},
+		// the hash tag is hashed
+		{key: "{foo}:otp", want: 12182},
+		{key: "user:{foo}", want: 12182},
+		// only the first hash tag is hashed
+		{key: "{foo}{bar}", want: 12182},
+		// "{bar" is the hash tag
+		{key: "foo{{bar}}zap", want: slot("{bar")},
+		// empty hash tag, the whole key is hashed
+		{key: "foo{}{bar}", want: int(crc16("foo{}{bar}")) % clusterSlots},
+		// unclosed hash tag, the whole key is hashed
+		{key: "foo{bar", want: int(crc16("foo{bar")) % clusterSlots},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, slot(tt.key))
+		})
+	}
+}
+
+func TestGroupBySlot(t *testing.T) {
+	keys := []string{"{a}:1", "{b}:1", "{a}:2"}
+
+	// standalone is not grouped
+	d := &Db{}
+	assert.Equal(t, [][]string{keys}, d.groupBySlot(keys))
+
+	// cluster is grouped by the slot in order of the first key
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{"localhost:0"}})
+	defer client.Close()
+
+	d = &Db{DB: client}
+	assert.Equal(t, [][]string{{"{a}:1", "{a}:2"}, {"{b}:1"}}, d.groupBySlot(keys))
+}
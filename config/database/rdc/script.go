@@ -0,0 +1,40 @@
+package rdc
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewScript create lua script, the script is executed with EVALSHA and loaded on NOSCRIPT, e.g.:
+//
+//	var incrMax = rdc.NewScript(`
+//		local current = redis.call("INCR", KEYS[1])
+//		if current > tonumber(ARGV[1]) then return -1 end
+//		return current
+//	`)
+//
+//	result, err := client.EvalScript(ctx, incrMax, []string{key}, 10)
+func NewScript(src string) *redis.Script {
+	return redis.NewScript(src)
+}
+
+func (d *Db) LoadScripts(ctx context.Context, scripts ...*redis.Script) error {
+	for _, script := range scripts {
+		// in the cluster mode, the script is loaded into every master
+		if err := script.Load(ctx, d.DB).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Db) EvalScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) (interface{}, error) {
+	result, err := script.Run(ctx, d.DB, keys, args...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package rdc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (d *Db) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	// maxLen less than 1 is not trimmed, otherwise the stream is trimmed approximately (MAXLEN ~)
+	id, err := d.DB.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: maxLen > 0,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (d *Db) XRange(ctx context.Context, stream, start, stop string, count int64) ([]redis.XMessage, error) {
+	// start and stop are the message id, "-" and "+" are the first and the last message
+	var cmd *redis.XMessageSliceCmd
+	if count > 0 {
+		cmd = d.DB.XRangeN(ctx, stream, start, stop, count)
+	} else {
+		cmd = d.DB.XRange(ctx, stream, start, stop)
+	}
+
+	result, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) XLen(ctx context.Context, stream string) (int64, error) {
+	result, err := d.DB.XLen(ctx, stream).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) XDel(ctx context.Context, stream string, ids ...string) (int64, error) {
+	result, err := d.DB.XDel(ctx, stream, ids...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) XTrim(ctx context.Context, stream string, maxLen int64) (int64, error) {
+	result, err := d.DB.XTrimMaxLenApprox(ctx, stream, maxLen, 0).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	// the stream is created when not exists, BUSYGROUP error is ignored
+	err := d.DB.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	return nil
+}
+
+func (d *Db) XReadGroup(ctx context.Context, group, consumer, stream string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	// only the new messages (>) of the group, redis.Nil when the block timeout exceeded
+	streams, err := d.DB.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []redis.XMessage
+	for _, s := range streams {
+		result = append(result, s.Messages...)
+	}
+
+	return result, nil
+}
+
+func (d *Db) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	result, err := d.DB.XAck(ctx, stream, group, ids...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) XAutoClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64) ([]redis.XMessage, string, error) {
+	// returns the claimed messages and the start id of the next call, "0-0" when all pending messages are scanned
+	messages, next, err := d.DB.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return messages, next, nil
+}
+
+func (d *Db) XPending(ctx context.Context, stream, group, start, end string, count int64) ([]redis.XPendingExt, error) {
+	result, err := d.DB.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  start,
+		End:    end,
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
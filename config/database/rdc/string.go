@@ -0,0 +1,89 @@
+package rdc
+
+import (
+	"context"
+)
+
+func (d *Db) GetSet(ctx context.Context, key string, value interface{}) (string, error) {
+	// redis.Nil when the key does not exist before
+	resp, err := d.DB.GetSet(ctx, key, value).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (d *Db) GetDel(ctx context.Context, key string) (string, error) {
+	resp, err := d.DB.GetDel(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
+
+func (d *Db) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	result, err := d.DB.IncrBy(ctx, key, value).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) SetBit(ctx context.Context, key string, offset int64, value int) (int64, error) {
+	// returns the previous bit
+	result, err := d.DB.SetBit(ctx, key, offset, value).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) GetBit(ctx context.Context, key string, offset int64) (int64, error) {
+	result, err := d.DB.GetBit(ctx, key, offset).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) BitCount(ctx context.Context, key string) (int64, error) {
+	result, err := d.DB.BitCount(ctx, key, nil).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) PFAdd(ctx context.Context, key string, elements ...interface{}) (bool, error) {
+	// returns true when the estimated cardinality is changed
+	result, err := d.DB.PFAdd(ctx, key, elements...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return result == 1, nil
+}
+
+func (d *Db) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	result, err := d.DB.PFCount(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) PFMerge(ctx context.Context, dest string, keys ...string) error {
+	err := d.DB.PFMerge(ctx, dest, keys...).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
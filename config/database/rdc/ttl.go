@@ -0,0 +1,148 @@
+package rdc
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/zone"
+)
+
+// TTLMode mode of the ttl policy
+type TTLMode int
+
+const (
+	// TTLEndOfDay the key is expired at the end of the day (midnight) in the location, default is Asia/Jakarta
+	TTLEndOfDay TTLMode = iota
+	// TTLFixed the key is expired after the duration
+	TTLFixed
+	// TTLNone the key is not expired, SET and MSET remove the existing ttl
+	TTLNone
+	// TTLKeep the existing ttl of the key is kept (SET KEEPTTL), the new key is not expired
+	TTLKeep
+)
+
+var ttlModes = map[string]TTLMode{
+	"end_of_day": TTLEndOfDay,
+	"fixed":      TTLFixed,
+	"none":       TTLNone,
+	"keep":       TTLKeep,
+}
+
+// ParseTTLMode returns the mode of the name, e.g.: end_of_day, fixed, none, keep
+func ParseTTLMode(name string) (TTLMode, error) {
+	mode, ok := ttlModes[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown redis ttl policy %s", name)
+	}
+
+	return mode, nil
+}
+
+// TTLPolicy is the expiration of the write commands (Set, MSet, HSet, HMSet, DoSadd) when the ttl is not set
+// on the call. the zero value is the end of the day in Asia/Jakarta
+type TTLPolicy struct {
+	Mode TTLMode
+	// Duration of TTLFixed
+	Duration time.Duration
+	// Location of TTLEndOfDay
+	Location *time.Location
+	// Jitter the random duration [0, jitter) added to the ttl, so the keys written together are not expired at the same moment
+	Jitter time.Duration
+}
+
+// EndOfDayTTL expire the keys at the end of the day in the location, nil location is Asia/Jakarta
+func EndOfDayTTL(location *time.Location) TTLPolicy {
+	return TTLPolicy{Mode: TTLEndOfDay, Location: location}
+}
+
+// FixedTTL expire the keys after the duration
+func FixedTTL(duration time.Duration) TTLPolicy {
+	return TTLPolicy{Mode: TTLFixed, Duration: duration}
+}
+
+// NoTTL the keys are not expired
+func NoTTL() TTLPolicy {
+	return TTLPolicy{Mode: TTLNone}
+}
+
+// KeepTTL keep the existing ttl of the keys
+func KeepTTL() TTLPolicy {
+	return TTLPolicy{Mode: TTLKeep}
+}
+
+// WriteOption option of the write command, override the ttl policy of the connection, e.g.:
+//
+//	client.Set(ctx, key, value, rdc.WithTTL(time.Hour), rdc.WithJitter(time.Minute))
+type WriteOption func(*TTLPolicy)
+
+// WithTTL expire the key after the duration, 0 is not expired
+func WithTTL(ttl time.Duration) WriteOption {
+	return func(p *TTLPolicy) {
+		p.Mode = TTLFixed
+		p.Duration = ttl
+	}
+}
+
+// WithEndOfDay expire the key at the end of the day in the location, nil location is Asia/Jakarta
+func WithEndOfDay(location *time.Location) WriteOption {
+	return func(p *TTLPolicy) {
+		p.Mode = TTLEndOfDay
+		p.Location = location
+	}
+}
+
+// WithoutTTL the key is not expired
+func WithoutTTL() WriteOption {
+	return func(p *TTLPolicy) {
+		p.Mode = TTLNone
+	}
+}
+
+// WithKeepTTL keep the existing ttl of the key
+func WithKeepTTL() WriteOption {
+	return func(p *TTLPolicy) {
+		p.Mode = TTLKeep
+	}
+}
+
+// WithJitter add random duration [0, jitter) to the ttl, 0 disable the jitter of the policy
+func WithJitter(jitter time.Duration) WriteOption {
+	return func(p *TTLPolicy) {
+		p.Jitter = jitter
+	}
+}
+
+// expiration returns the ttl of the write command with the options, 0 is not expired.
+// keep is true when the existing ttl is kept
+func (d *Db) expiration(opts ...WriteOption) (ttl time.Duration, keep bool) {
+	policy := d.TTLPolicy
+	for _, opt := range opts {
+		opt(&policy)
+	}
+
+	switch policy.Mode {
+	case TTLKeep:
+		return 0, true
+	case TTLNone:
+		return 0, false
+	case TTLFixed:
+		ttl = policy.Duration
+	default:
+		location := policy.Location
+		if location == nil {
+			location = zone.TzJakarta()
+		}
+
+		now := time.Now().In(location)
+		nd := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()) // get the next day
+		ttl = nd.Sub(now)
+	}
+
+	if ttl > 0 && policy.Jitter > 0 {
+		ttl += time.Duration(rand.Int63n(int64(policy.Jitter)))
+	}
+
+	return ttl, false
+}
@@ -0,0 +1,127 @@
+package rdc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTTLMode(t *testing.T) {
+	for name, want := range map[string]TTLMode{"end_of_day": TTLEndOfDay, "FIXED": TTLFixed, "none": TTLNone, "keep": TTLKeep} {
+		mode, err := ParseTTLMode(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, mode)
+	}
+
+	_, err := ParseTTLMode("forever")
+	assert.Error(t, err)
+}
+
+func TestExpiration(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   TTLPolicy
+		opts     []WriteOption
+		min, max time.Duration
+		keep     bool
+	}{
+		{
+			name:   "fixed",
+			policy: FixedTTL(time.Hour),
+			min:    time.Hour,
+			max:    time.Hour,
+		},
+		{
+			name:   "fixed with jitter",
+			policy: TTLPolicy{Mode: TTLFixed, Duration: time.Hour, Jitter: time.Minute},
+			min:    time.Hour,
+			max:    time.Hour + time.Minute,
+		},
+		{
+			name:   "none",
+			policy: NoTTL(),
+		},
+		{
+			name:   "none ignore the jitter",
+			policy: TTLPolicy{Mode: TTLNone, Jitter: time.Minute},
+		},
+		{
+			name:   "keep",
+			policy: KeepTTL(),
+			keep:   true,
+		},
+		{
+			name:   "end of day",
+			policy: EndOfDayTTL(time.UTC),
+			min:    time.Nanosecond,
+			max:    24 * time.Hour,
+		},
+		{
+			name:   "the zero value is end of day",
+			policy: TTLPolicy{},
+			min:    time.Nanosecond,
+			max:    24 * time.Hour,
+		},
+		{
+			name:   "with ttl override the policy",
+			policy: KeepTTL(),
+			opts:   []WriteOption{WithTTL(time.Minute)},
+			min:    time.Minute,
+			max:    time.Minute,
+		},
+		{
+			name:   "with ttl 0 is not expired",
+			policy: FixedTTL(time.Hour),
+			opts:   []WriteOption{WithTTL(0)},
+		},
+		{
+			name:   "without ttl",
+			policy: FixedTTL(time.Hour),
+			opts:   []WriteOption{WithoutTTL()},
+		},
+		{
+			name:   "with keep ttl",
+			policy: FixedTTL(time.Hour),
+			opts:   []WriteOption{WithKeepTTL()},
+			keep:   true,
+		},
+		{
+			name:   "with jitter 0 disable the jitter of the policy",
+			policy: TTLPolicy{Mode: TTLFixed, Duration: time.Hour, Jitter: time.Minute},
+			opts:   []WriteOption{WithJitter(0)},
+			min:    time.Hour,
+			max:    time.Hour,
+		},
+		{
+			name:   "with end of day",
+			policy: FixedTTL(time.Hour),
+			opts:   []WriteOption{WithEndOfDay(time.UTC)},
+			min:    time.Nanosecond,
+			max:    24 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Db{TTLPolicy: tt.policy}
+			ttl, keep := d.expiration(tt.opts...)
+
+			assert.Equal(t, tt.keep, keep)
+			assert.GreaterOrEqual(t, ttl, tt.min)
+			assert.LessOrEqual(t, ttl, tt.max)
+		})
+	}
+}
+
+func TestExpirationEndOfDay(t *testing.T) {
+	loc := time.FixedZone("UTC+7", 7*60*60)
+	d := &Db{TTLPolicy: EndOfDayTTL(loc)}
+
+	ttl, _ := d.expiration()
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+
+	// the key is expired at the midnight of the location
+	assert.WithinDuration(t, midnight, now.Add(ttl), time.Second)
+}
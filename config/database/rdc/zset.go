@@ -0,0 +1,100 @@
+package rdc
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (d *Db) ZIncrBy(ctx context.Context, key string, incr float64, member string) (float64, error) {
+	result, err := d.DB.ZIncrBy(ctx, key, incr, member).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZScore(ctx context.Context, key, member string) (float64, error) {
+	// redis.Nil when the member does not exist
+	result, err := d.DB.ZScore(ctx, key, member).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRank(ctx context.Context, key, member string) (int64, error) {
+	// redis.Nil when the member does not exist
+	result, err := d.DB.ZRank(ctx, key, member).Result()
+	if err != nil {
+		return -1, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	// redis.Nil when the member does not exist
+	result, err := d.DB.ZRevRank(ctx, key, member).Result()
+	if err != nil {
+		return -1, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	result, err := d.DB.ZRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	result, err := d.DB.ZRevRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]redis.Z, error) {
+	result, err := d.DB.ZRangeWithScores(ctx, key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZCard(ctx context.Context, key string) (int64, error) {
+	result, err := d.DB.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZCount(ctx context.Context, key string, min, max string) (int64, error) {
+	result, err := d.DB.ZCount(ctx, key, min, max).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (d *Db) ZRemRangeByScore(ctx context.Context, key string, min, max string) (int64, error) {
+	result, err := d.DB.ZRemRangeByScore(ctx, key, min, max).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
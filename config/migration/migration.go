@@ -0,0 +1,313 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/lib/pq"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Command of the migrations
+const (
+	Up      = "up"
+	Down    = "down"
+	Redo    = "redo"
+	Status  = "status"
+	Version = "version"
+	Create  = "create"
+)
+
+// ErrNoMigrations the migrations directory is not exists or empty
+var ErrNoMigrations = errors.New("no have migrations")
+
+// Migrator run the goose migrations of the database
+type Migrator struct {
+	opt option
+}
+
+// New create new migrator, by default the migrations are read from PATH_DB_MIGRATIONS
+// and applied into DSN_MASTER with the postgres advisory lock
+func New(opts ...OptionFunc) *Migrator {
+	m := &Migrator{opt: getDefaultOption()}
+	for _, opt := range opts {
+		opt(&m.opt)
+	}
+
+	return m
+}
+
+// Run execute the command with arguments, used by the CLI, e.g.:
+//
+//	// go run main.go migrate up
+//	if len(os.Args) > 2 && os.Args[1] == "migrate" {
+//		_ = migration.New(migration.SetFatal(true)).Run(ctx, os.Args[2], os.Args[3:]...)
+//		return
+//	}
+//
+// the commands are:
+//   - up [version]: apply all or up to version migrations
+//   - down [version]: roll back the last or down to version migrations
+//   - redo: roll back and apply again the last migration
+//   - status: print the status of all migrations
+//   - version: print the current version of the database
+//   - create <name> [sql|go]: create new migration file in the directory
+func (m *Migrator) Run(ctx context.Context, command string, args ...string) error {
+	switch command {
+	case Up, Down:
+		if len(args) < 1 {
+			if command == Up {
+				return m.Up(ctx)
+			}
+			return m.Down(ctx)
+		}
+
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return m.result(command, fmt.Errorf("invalid version %s: %w", args[0], err))
+		}
+
+		if command == Up {
+			return m.UpTo(ctx, version)
+		}
+		return m.DownTo(ctx, version)
+	case Redo:
+		return m.Redo(ctx)
+	case Status:
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+			appliedAt := "Pending"
+			if s.State == goose.StateApplied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-20s %-8s %s\n", appliedAt, s.State, s.Source.Path)
+		}
+		return nil
+	case Version:
+		version, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d\n", version)
+		return nil
+	case Create:
+		if len(args) < 1 {
+			return m.result(command, errors.New("migration name not yet set. usage: create <name> [sql|go]"))
+		}
+
+		migrationType := "sql"
+		if len(args) > 1 {
+			migrationType = args[1]
+		}
+		return m.Create(args[0], migrationType)
+	}
+
+	return m.result(command, fmt.Errorf("unknown migrations command %s", command))
+}
+
+// Up apply all pending migrations
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withProvider(ctx, Up, func(ctx context.Context, p *goose.Provider) error {
+		results, err := p.Up(ctx)
+		printResults(results...)
+		return err
+	})
+}
+
+// UpTo apply the pending migrations up to version (inclusive)
+func (m *Migrator) UpTo(ctx context.Context, version int64) error {
+	return m.withProvider(ctx, Up, func(ctx context.Context, p *goose.Provider) error {
+		results, err := p.UpTo(ctx, version)
+		printResults(results...)
+		return err
+	})
+}
+
+// Down roll back the last applied migration
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withProvider(ctx, Down, func(ctx context.Context, p *goose.Provider) error {
+		result, err := p.Down(ctx)
+		printResults(result)
+		return err
+	})
+}
+
+// DownTo roll back the applied migrations down to version (exclusive), 0 roll back all migrations
+func (m *Migrator) DownTo(ctx context.Context, version int64) error {
+	return m.withProvider(ctx, Down, func(ctx context.Context, p *goose.Provider) error {
+		results, err := p.DownTo(ctx, version)
+		printResults(results...)
+		return err
+	})
+}
+
+// Redo roll back and apply again the last applied migration
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withProvider(ctx, Redo, func(ctx context.Context, p *goose.Provider) error {
+		result, err := p.Down(ctx)
+		printResults(result)
+		if err != nil {
+			return err
+		}
+
+		result, err = p.ApplyVersion(ctx, result.Source.Version, true)
+		printResults(result)
+		return err
+	})
+}
+
+// Status returns the status of all migrations
+func (m *Migrator) Status(ctx context.Context) (statuses []*goose.MigrationStatus, err error) {
+	err = m.withProvider(ctx, Status, func(ctx context.Context, p *goose.Provider) error {
+		statuses, err = p.Status(ctx)
+		return err
+	})
+
+	return
+}
+
+// Version returns the current version of the database
+func (m *Migrator) Version(ctx context.Context) (version int64, err error) {
+	err = m.withProvider(ctx, Version, func(ctx context.Context, p *goose.Provider) error {
+		version, err = p.GetDBVersion(ctx)
+		return err
+	})
+
+	return
+}
+
+// Create create new migration file in the directory, migrationType is sql or go.
+// it is not supported for the embedded migrations
+func (m *Migrator) Create(name, migrationType string) error {
+	if m.opt.fsys != nil {
+		return m.result(Create, errors.New("cannot create migration into the embedded migrations"))
+	}
+
+	if err := os.MkdirAll(m.opt.dir, os.ModePerm); err != nil {
+		return m.result(Create, err)
+	}
+
+	return m.result(Create, goose.Create(nil, m.opt.dir, name, migrationType))
+}
+
+// withProvider open the database and run fn with the goose provider, the provider hold the advisory lock
+// during the command, so the other instances wait until the lock released
+func (m *Migrator) withProvider(ctx context.Context, command string, fn func(context.Context, *goose.Provider) error) (err error) {
+	defer func() {
+		// nothing to apply
+		if errors.Is(err, ErrNoMigrations) && command == Up {
+			logger.RedItalic(err.Error())
+			err = nil
+		}
+
+		err = m.result(command, err)
+	}()
+
+	fsys, err := m.migrations()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.opt.timeout)
+	defer cancel()
+
+	db, err := sql.Open(m.opt.driver, m.opt.dsn)
+	if err != nil {
+		return fmt.Errorf("failed open connection database for migrations: %w", err)
+	}
+	defer db.Close()
+
+	opts := []goose.ProviderOption{
+		goose.WithAllowOutofOrder(m.opt.allowOutOfOrder),
+		goose.WithVerbose(m.opt.verbose),
+	}
+	if m.opt.lock {
+		locker, err := lock.NewPostgresSessionLocker(lock.WithLockID(m.opt.lockId))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, goose.WithSessionLocker(locker))
+	}
+
+	p, err := goose.NewProvider(goose.DialectPostgres, db, fsys, opts...)
+	if err != nil {
+		if errors.Is(err, goose.ErrNoMigrations) {
+			return ErrNoMigrations
+		}
+		return err
+	}
+	defer p.Close()
+
+	return fn(ctx, p)
+}
+
+// migrations returns the file system of the migrations directory
+func (m *Migrator) migrations() (fs.FS, error) {
+	if m.opt.fsys != nil {
+		if m.opt.dir == "" || m.opt.dir == "." {
+			return m.opt.fsys, nil
+		}
+
+		fsys, err := fs.Sub(m.opt.fsys, m.opt.dir)
+		if err != nil {
+			return nil, err
+		}
+		return fsys, nil
+	}
+
+	info, err := os.Stat(m.opt.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoMigrations
+		}
+
+		return nil, fmt.Errorf("get folder path of %s is error: %w", m.opt.dir, err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not directory", m.opt.dir)
+	}
+
+	return os.DirFS(m.opt.dir), nil
+}
+
+// result log the error of the command, exit the application when the fatal option is set
+func (m *Migrator) result(command string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if m.opt.fatal {
+		logger.Log.Fatalf("migrations %s error: %s", command, err)
+	}
+
+	logger.Red(fmt.Sprintf("migrations %s error: %s", command, err))
+	return err
+}
+
+func printResults(results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		if result.Error != nil {
+			logger.Red(result.String())
+			continue
+		}
+		logger.GreenItalic(result.String())
+	}
+}
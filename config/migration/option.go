@@ -0,0 +1,113 @@
+package migration
+
+import (
+	"io/fs"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/pressly/goose/v3/lock"
+)
+
+type option struct {
+	dsn             string
+	driver          string
+	dir             string
+	fsys            fs.FS
+	timeout         time.Duration
+	fatal           bool
+	lock            bool
+	lockId          int64
+	allowOutOfOrder bool
+	verbose         bool
+}
+
+// OptionFunc option func for migrator
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		dsn:     env.GetString("DSN_MASTER"),
+		driver:  env.GetString("DB_MIGRATIONS_DRIVER", "postgres"),
+		dir:     env.GetString("PATH_DB_MIGRATIONS", "db/migrations"),
+		timeout: env.GetDuration("DB_MIGRATIONS_TIMEOUT", 3*time.Minute),
+		fatal:   env.GetBool("DB_MIGRATIONS_FATAL", false),
+		lock:    true,
+		lockId:  lock.DefaultLockID,
+	}
+}
+
+// SetDSN set the dsn of the target database, default is DSN_MASTER
+func SetDSN(dsn string) OptionFunc {
+	return func(o *option) {
+		o.dsn = dsn
+	}
+}
+
+// SetDriver set the database/sql driver, e.g.: postgres (lib/pq) or pgx
+func SetDriver(driver string) OptionFunc {
+	return func(o *option) {
+		o.driver = driver
+	}
+}
+
+// SetDir set the directory of the migrations, when the migrations are embedded (SetFS)
+// the directory is relative to the root of the embedded files
+func SetDir(dir string) OptionFunc {
+	return func(o *option) {
+		o.dir = dir
+	}
+}
+
+// SetFS set the embedded migrations, e.g.:
+//
+//	//go:embed db/migrations/*.sql
+//	var migrations embed.FS
+//
+//	migration.New(migration.SetFS(migrations))
+func SetFS(fsys fs.FS) OptionFunc {
+	return func(o *option) {
+		o.fsys = fsys
+	}
+}
+
+// SetTimeout set the timeout of each command
+func SetTimeout(timeout time.Duration) OptionFunc {
+	return func(o *option) {
+		o.timeout = timeout
+	}
+}
+
+// SetFatal exit the application when the command is failed, otherwise the error is logged and returned
+func SetFatal(fatal bool) OptionFunc {
+	return func(o *option) {
+		o.fatal = fatal
+	}
+}
+
+// SetLock enable the postgres advisory lock, so only one instance run the migrations at a time (default is enabled)
+func SetLock(enabled bool) OptionFunc {
+	return func(o *option) {
+		o.lock = enabled
+	}
+}
+
+// SetLockId set the id of the postgres advisory lock, the services which share the database must use different id
+func SetLockId(id int64) OptionFunc {
+	return func(o *option) {
+		o.lockId = id
+	}
+}
+
+// SetAllowOutOfOrder apply the missing migrations which version is lower than the current version
+func SetAllowOutOfOrder(allow bool) OptionFunc {
+	return func(o *option) {
+		o.allowOutOfOrder = allow
+	}
+}
+
+// SetVerbose print the executed statements
+func SetVerbose(verbose bool) OptionFunc {
+	return func(o *option) {
+		o.verbose = verbose
+	}
+}
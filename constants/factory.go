@@ -30,6 +30,10 @@ const (
 	NSQ Worker = "nsq"
 	// Kafka worker
 	Kafka Worker = "kafka"
+	// RedisTask worker
+	RedisTask Worker = "redis-task"
+	// RedisStream worker
+	RedisStream Worker = "redis-stream"
 )
 
 func (w Worker) String() string {
@@ -0,0 +1,207 @@
+package cronexpr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/logger"
+)
+
+const (
+	// layoutDate layout of holiday date
+	layoutDate = "2006-01-02"
+	// maxCalendarLookup maximum days to look up for the business day
+	maxCalendarLookup = 366
+)
+
+// Modifier is the behaviour of a schedule when the next time is not a business day
+type Modifier string
+
+const (
+	// SkipHoliday skip the run when the next time is a holiday
+	SkipHoliday Modifier = "skipholiday"
+	// NextBusinessDay move the run to the next business day
+	NextBusinessDay Modifier = "nextbusinessday"
+	// PreviousBusinessDay move the run to the previous business day
+	PreviousBusinessDay Modifier = "prevbusinessday"
+)
+
+// Holiday model
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// Calendar abstraction for checking holiday and business day
+type Calendar interface {
+	// IsHoliday returns true when the date of t is a holiday
+	IsHoliday(t time.Time) bool
+	// IsBusinessDay returns true when the date of t is not a weekend and not a holiday
+	IsBusinessDay(t time.Time) bool
+}
+
+type calendar struct {
+	holidays map[string]string
+	weekends map[time.Weekday]bool
+}
+
+var (
+	defaultCalendar     Calendar
+	defaultCalendarLock sync.Mutex
+)
+
+// NewCalendar creates a calendar with saturday and sunday as the weekend
+func NewCalendar(holidays ...Holiday) Calendar {
+	cal := &calendar{
+		holidays: make(map[string]string),
+		weekends: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+	}
+
+	for _, h := range holidays {
+		cal.holidays[h.Date.Format(layoutDate)] = h.Name
+	}
+
+	return cal
+}
+
+// IsHoliday method
+func (c *calendar) IsHoliday(t time.Time) bool {
+	_, ok := c.holidays[t.Format(layoutDate)]
+	return ok
+}
+
+// IsBusinessDay method
+func (c *calendar) IsBusinessDay(t time.Time) bool {
+	return !c.weekends[t.Weekday()] && !c.IsHoliday(t)
+}
+
+// DefaultCalendar returns the calendar used by descriptor @businessday
+//
+// when not set with SetDefaultCalendar, the holidays are loaded from env:
+//   - CRON_HOLIDAYS_FILE, path of the holiday file
+//   - CRON_HOLIDAYS, list of date separated by comma, e.g.: 2025-03-31,2025-04-01
+//   - CRON_HOLIDAYS_INDONESIA, include built-in Indonesian national holidays (default false)
+func DefaultCalendar() Calendar {
+	defaultCalendarLock.Lock()
+	defer defaultCalendarLock.Unlock()
+
+	if defaultCalendar != nil {
+		return defaultCalendar
+	}
+
+	var holidays []Holiday
+	if env.GetBool("CRON_HOLIDAYS_INDONESIA") {
+		holidays = append(holidays, IndonesiaHolidays()...)
+	}
+
+	if path := env.GetString("CRON_HOLIDAYS_FILE"); path != "" {
+		hs, err := HolidaysFromFile(path)
+		if err != nil {
+			logger.Red(fmt.Sprintf("cronexpr > failed to load holiday file %s: %s", path, err))
+		}
+		holidays = append(holidays, hs...)
+	}
+
+	hs, err := HolidaysFromEnv("CRON_HOLIDAYS")
+	if err != nil {
+		logger.Red(fmt.Sprintf("cronexpr > failed to load holidays from env: %s", err))
+	}
+	holidays = append(holidays, hs...)
+
+	defaultCalendar = NewCalendar(holidays...)
+	return defaultCalendar
+}
+
+// SetDefaultCalendar replace the default calendar
+func SetDefaultCalendar(cal Calendar) {
+	defaultCalendarLock.Lock()
+	defer defaultCalendarLock.Unlock()
+
+	defaultCalendar = cal
+}
+
+type calendarSchedule struct {
+	schedule Schedule
+	calendar Calendar
+	modifier Modifier
+}
+
+// WithCalendar wraps the schedule, the next time is adjusted by the modifier when it's not a business day
+func WithCalendar(schedule Schedule, cal Calendar, modifier Modifier) Schedule {
+	return &calendarSchedule{schedule: schedule, calendar: cal, modifier: modifier}
+}
+
+// Next returns the closest time after fromTime which match the schedule and the modifier
+func (cs *calendarSchedule) Next(fromTime time.Time) time.Time {
+	next := cs.schedule.Next(fromTime)
+	for i := 0; i < maxCalendarLookup && !next.IsZero(); i++ {
+		switch cs.modifier {
+		case SkipHoliday:
+			if !cs.calendar.IsHoliday(next) {
+				return next
+			}
+		case NextBusinessDay:
+			return cs.shift(next, 1)
+		case PreviousBusinessDay:
+			// when the previous business day already passed, use the next occurrence
+			if prev := cs.shift(next, -1); prev.After(fromTime) {
+				return prev
+			}
+		default:
+			return next
+		}
+
+		next = cs.schedule.Next(next)
+	}
+
+	return time.Time{}
+}
+
+// NextInterval method
+func (cs *calendarSchedule) NextInterval(fromTime time.Time) time.Duration {
+	return nextInterval(cs, fromTime)
+}
+
+// Prev method
+func (cs *calendarSchedule) Prev(fromTime time.Time) time.Time {
+	return prev(cs, fromTime)
+}
+
+// NextN method
+func (cs *calendarSchedule) NextN(fromTime time.Time, n int) []time.Time {
+	return nextN(cs, fromTime, n)
+}
+
+// Between method
+func (cs *calendarSchedule) Between(from, to time.Time) []time.Time {
+	return between(cs, from, to)
+}
+
+// Describe method
+func (cs *calendarSchedule) Describe() string {
+	switch cs.modifier {
+	case SkipHoliday:
+		return cs.schedule.Describe() + ", except on holidays"
+	case NextBusinessDay:
+		return cs.schedule.Describe() + ", moved to the next business day when it's not a business day"
+	case PreviousBusinessDay:
+		return cs.schedule.Describe() + ", moved to the previous business day when it's not a business day"
+	default:
+		return cs.schedule.Describe()
+	}
+}
+
+// shift move t day by day until the business day found
+func (cs *calendarSchedule) shift(t time.Time, days int) time.Time {
+	for i := 0; i < maxCalendarLookup; i++ {
+		if cs.calendar.IsBusinessDay(t) {
+			return t
+		}
+
+		t = t.AddDate(0, 0, days)
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,152 @@
+package cronexpr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// holidayCalendar every day is a holiday
+type holidayCalendar struct{}
+
+func (holidayCalendar) IsHoliday(time.Time) bool     { return true }
+func (holidayCalendar) IsBusinessDay(time.Time) bool { return false }
+
+func TestCalendar(t *testing.T) {
+	cal := NewCalendar(Holiday{Date: time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC), Name: "Hari Raya Natal"})
+
+	// thursday, holiday
+	assert.True(t, cal.IsHoliday(time.Date(2025, 12, 25, 7, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsBusinessDay(time.Date(2025, 12, 25, 7, 0, 0, 0, time.UTC)))
+	// friday
+	assert.False(t, cal.IsHoliday(time.Date(2025, 12, 26, 7, 0, 0, 0, time.UTC)))
+	assert.True(t, cal.IsBusinessDay(time.Date(2025, 12, 26, 7, 0, 0, 0, time.UTC)))
+	// saturday, weekend is not a holiday
+	assert.False(t, cal.IsHoliday(time.Date(2025, 12, 27, 7, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsBusinessDay(time.Date(2025, 12, 27, 7, 0, 0, 0, time.UTC)))
+}
+
+func TestDefaultCalendar(t *testing.T) {
+	SetDefaultCalendar(nil)
+	t.Cleanup(func() { SetDefaultCalendar(nil) })
+
+	path := filepath.Join(t.TempDir(), "holidays.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("2025-12-31,Libur Bank\n"), 0o600))
+
+	t.Setenv("CRON_HOLIDAYS_INDONESIA", "true")
+	t.Setenv("CRON_HOLIDAYS_FILE", path)
+	t.Setenv("CRON_HOLIDAYS", "2025-12-24")
+
+	cal := DefaultCalendar()
+	assert.True(t, cal.IsHoliday(time.Date(2025, 12, 25, 7, 0, 0, 0, time.UTC)), "built-in")
+	assert.True(t, cal.IsHoliday(time.Date(2025, 12, 31, 7, 0, 0, 0, time.UTC)), "file")
+	assert.True(t, cal.IsHoliday(time.Date(2025, 12, 24, 7, 0, 0, 0, time.UTC)), "env")
+	assert.False(t, cal.IsHoliday(time.Date(2025, 12, 30, 7, 0, 0, 0, time.UTC)))
+	assert.Same(t, cal, DefaultCalendar())
+
+	custom := NewCalendar()
+	SetDefaultCalendar(custom)
+	assert.Same(t, custom, DefaultCalendar())
+}
+
+func TestWithCalendar(t *testing.T) {
+	cal := NewCalendar(Holiday{Date: time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)})
+
+	tests := []struct {
+		name     string
+		modifier Modifier
+		from     time.Time
+		want     time.Time
+	}{
+		{
+			name:     "skip holiday",
+			modifier: SkipHoliday,
+			from:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 1, 25, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skip holiday keeps the weekend",
+			modifier: SkipHoliday,
+			from:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 10, 25, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next business day after holiday",
+			modifier: NextBusinessDay,
+			from:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 12, 26, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "next business day after weekend",
+			modifier: NextBusinessDay,
+			from:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 10, 27, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "previous business day before holiday",
+			modifier: PreviousBusinessDay,
+			from:     time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 12, 24, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "previous business day before weekend",
+			modifier: PreviousBusinessDay,
+			from:     time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 10, 24, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "previous business day already passed",
+			modifier: PreviousBusinessDay,
+			from:     time.Date(2025, 10, 24, 8, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 11, 25, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "business day is not moved",
+			modifier: NextBusinessDay,
+			from:     time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 11, 25, 7, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// every 25th at 07:00
+			s := WithCalendar(MustParse("0 7 25 * *"), cal, tt.modifier)
+			assert.Equal(t, tt.want, s.Next(tt.from))
+			assert.Equal(t, tt.want.Sub(tt.from), s.NextInterval(tt.from))
+		})
+	}
+}
+
+func TestWithCalendarNoNext(t *testing.T) {
+	s := WithCalendar(MustParse("0 7 * * *"), holidayCalendar{}, SkipHoliday)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, s.Next(from).IsZero())
+	assert.Equal(t, time.Duration(0), s.NextInterval(from))
+}
+
+func TestBusinessDay(t *testing.T) {
+	SetDefaultCalendar(NewCalendar(Holiday{Date: time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC)}))
+	t.Cleanup(func() { SetDefaultCalendar(nil) })
+
+	s, err := ParseInLocation("07:00@businessday", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 12, 24, 7, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 26, 7, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 29, 7, 0, 0, 0, time.UTC),
+	}, s.NextN(time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), 3))
+
+	duration, nextDuration, err := ParseDurationInLocation("07:00@businessday", time.UTC)
+	assert.NoError(t, err)
+	now := time.Now()
+	assert.InDelta(t, float64(s.Next(now).Sub(now)), float64(duration), float64(time.Second))
+	assert.Equal(t, oneDay, nextDuration)
+
+	_, _, err = ParseDurationInLocation("7:0@businessday", time.UTC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package cronexpr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	dowNames   = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+)
+
+// Describe returns human-readable cron expression, e.g.:
+//
+//	0 0 7 * * 1-5 * -> at 07:00:00, on Monday-Friday
+func (expr *expression) Describe() string {
+	var parts []string
+
+	// time of day
+	if len(expr.hourList) == 1 && len(expr.minuteList) == 1 && len(expr.secondList) == 1 {
+		parts = append(parts, fmt.Sprintf("at %02d:%02d:%02d", expr.hourList[0], expr.minuteList[0], expr.secondList[0]))
+	} else {
+		parts = append(
+			parts,
+			fmt.Sprintf(
+				"at second %s, minute %s, hour %s",
+				describeList(expr.secondList, secondDescriptor, nil),
+				describeList(expr.minuteList, minuteDescriptor, nil),
+				describeList(expr.hourList, hourDescriptor, nil),
+			),
+		)
+	}
+
+	// day of month and day of week, the day matches when either field matches
+	var days []string
+	if expr.daysOfMonthRestricted {
+		days = append(days, "on "+expr.describeDaysOfMonth())
+	}
+	if expr.daysOfWeekRestricted {
+		days = append(days, "on "+expr.describeDaysOfWeek())
+	}
+	if len(days) > 0 {
+		parts = append(parts, strings.Join(days, " or "))
+	}
+
+	if len(expr.monthList) < len(monthDescriptor.defaultList) {
+		parts = append(parts, "in "+describeList(expr.monthList, monthDescriptor, monthNames))
+	}
+
+	if len(expr.yearList) < len(yearDescriptor.defaultList) {
+		parts = append(parts, "in year "+describeList(expr.yearList, yearDescriptor, nil))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (expr *expression) describeDaysOfMonth() string {
+	var items []string
+	if len(expr.daysOfMonth) > 0 {
+		items = append(items, "day-of-month "+describeList(toList(expr.daysOfMonth), domDescriptor, nil))
+	}
+	for _, v := range toList(expr.workdaysOfMonth) {
+		items = append(items, fmt.Sprintf("the nearest weekday of day-of-month %d", v))
+	}
+	if expr.lastDayOfMonth {
+		items = append(items, "the last day of month")
+	}
+	if expr.lastWorkdayOfMonth {
+		items = append(items, "the last weekday of month")
+	}
+
+	return strings.Join(items, " or ")
+}
+
+func (expr *expression) describeDaysOfWeek() string {
+	var items []string
+	if len(expr.daysOfWeek) > 0 {
+		items = append(items, describeList(toList(expr.daysOfWeek), dowDescriptor, dowNames))
+	}
+	for _, v := range toList(expr.specificWeekDaysOfWeek) {
+		items = append(items, fmt.Sprintf("the %s %s of month", ordinal(v/7+1), dowNames[v%7]))
+	}
+	for _, v := range toList(expr.lastWeekDaysOfWeek) {
+		items = append(items, fmt.Sprintf("the last %s of month", dowNames[v]))
+	}
+
+	return strings.Join(items, " or ")
+}
+
+// describeList returns "every" for full list, otherwise compact list with range, e.g.: 1-5,10,15
+func describeList(list []int, desc fieldDescriptor, names []string) string {
+	if len(list) >= desc.max-desc.min+1 {
+		return "every " + desc.name
+	}
+
+	name := func(v int) string {
+		if names != nil && v < len(names) {
+			return names[v]
+		}
+		return strconv.Itoa(v)
+	}
+
+	sorted := append([]int(nil), list...)
+	sort.Ints(sorted)
+
+	var items []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 {
+			items = append(items, name(sorted[i])+"-"+name(sorted[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, name(sorted[k]))
+			}
+		}
+		i = j + 1
+	}
+
+	return strings.Join(items, ",")
+}
+
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
@@ -0,0 +1,186 @@
+package cronexpr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/zone"
+)
+
+// descriptorSchedule repeat the schedule with calendar period (day, month, year) from the anchor run
+type descriptorSchedule struct {
+	descriptor          string
+	year, day           int
+	month               time.Month
+	hour, minute        int
+	years, months, days int
+	location            *time.Location
+	approximatePeriod   time.Duration
+}
+
+// parseDescriptor parse descriptor HH:mm@daily|weekly|monthly|yearly|businessday,
+// ok is false when the descriptor is not calendar based
+func parseDescriptor(t string, loc *time.Location) (schedule Schedule, ok bool, err error) {
+	at, descriptor, found := strings.Cut(t, "@")
+	if !found || at == "" {
+		return nil, false, nil
+	}
+
+	descriptor = strings.ToLower(descriptor)
+	switch descriptor {
+	case daily, weekly, monthly, yearly, businessday:
+	default:
+		return nil, false, nil
+	}
+
+	ts, err := time.Parse(layoutTime, at)
+	if err != nil {
+		return nil, true, errors.New("time format error. must be HH:mm")
+	}
+
+	if descriptor == businessday {
+		schedule, err = Parse(fmt.Sprintf("0 %d %d * * 1-5 *", ts.Minute(), ts.Hour()))
+		if err != nil {
+			return nil, true, err
+		}
+
+		return WithCalendar(schedule, DefaultCalendar(), SkipHoliday), true, nil
+	}
+
+	if loc == nil {
+		loc = zone.TzJakarta()
+	}
+
+	// daily and weekly are anchored to the closest HH:mm from now,
+	// monthly and yearly are anchored to the 1st of the month and 1 January like the standard cron
+	now := time.Now().In(loc)
+	anchor := time.Date(now.Year(), now.Month(), now.Day(), ts.Hour(), ts.Minute(), 0, 0, loc)
+	if !anchor.After(now) {
+		anchor = time.Date(now.Year(), now.Month(), now.Day()+1, ts.Hour(), ts.Minute(), 0, 0, loc)
+	}
+
+	ds := &descriptorSchedule{
+		descriptor: descriptor,
+		year:       anchor.Year(),
+		month:      anchor.Month(),
+		day:        anchor.Day(),
+		hour:       ts.Hour(),
+		minute:     ts.Minute(),
+		location:   loc,
+	}
+
+	switch descriptor {
+	case daily:
+		ds.days = 1
+		ds.approximatePeriod = oneDay
+	case weekly:
+		ds.days = 7
+		ds.approximatePeriod = oneWeek
+	case monthly:
+		ds.day = 1
+		ds.months = 1
+		ds.approximatePeriod = oneMonth
+	case yearly:
+		ds.day, ds.month = 1, time.January
+		ds.years = 1
+		ds.approximatePeriod = oneYear
+	}
+
+	return ds, true, nil
+}
+
+// at returns the k-th run, counted from the anchor run
+func (ds *descriptorSchedule) at(k int) time.Time {
+	year := ds.year + k*ds.years
+	month := ds.month + time.Month(k*ds.months)
+	day := ds.day + k*ds.days
+
+	// time.Date keeps the wall clock, so the run is not shifted by DST transition
+	t := time.Date(year, month, day, ds.hour, ds.minute, 0, 0, ds.location)
+	if t.Hour() == ds.hour && t.Minute() == ds.minute {
+		return t
+	}
+
+	// the wall clock is skipped by DST transition, run right after the transition
+	// with the offset before it, e.g.: 02:30 is 03:30 when the clock jumps from 02:00 to 03:00
+	_, offset := t.Zone()
+	after := time.Date(year, month, day, ds.hour, ds.minute, 0, 0, time.UTC).Add(-time.Duration(offset) * time.Second)
+	if after.After(t) {
+		return after.In(ds.location)
+	}
+
+	return t
+}
+
+// index returns estimation of k-th run around t
+func (ds *descriptorSchedule) index(t time.Time) int {
+	return int(math.Floor(float64(t.Sub(ds.at(0))) / float64(ds.approximatePeriod)))
+}
+
+// Next method
+func (ds *descriptorSchedule) Next(fromTime time.Time) time.Time {
+	if fromTime.IsZero() {
+		return fromTime
+	}
+
+	k := ds.index(fromTime)
+	for ds.at(k).After(fromTime) {
+		k--
+	}
+	for !ds.at(k).After(fromTime) {
+		k++
+	}
+
+	return ds.at(k).In(fromTime.Location())
+}
+
+// NextInterval method
+func (ds *descriptorSchedule) NextInterval(fromTime time.Time) time.Duration {
+	return nextInterval(ds, fromTime)
+}
+
+// Prev method
+func (ds *descriptorSchedule) Prev(fromTime time.Time) time.Time {
+	if fromTime.IsZero() {
+		return fromTime
+	}
+
+	k := ds.index(fromTime)
+	for !ds.at(k).Before(fromTime) {
+		k--
+	}
+	for ds.at(k + 1).Before(fromTime) {
+		k++
+	}
+
+	return ds.at(k).In(fromTime.Location())
+}
+
+// NextN method
+func (ds *descriptorSchedule) NextN(fromTime time.Time, n int) []time.Time {
+	return nextN(ds, fromTime, n)
+}
+
+// Between method
+func (ds *descriptorSchedule) Between(from, to time.Time) []time.Time {
+	return between(ds, from, to)
+}
+
+// Describe method
+func (ds *descriptorSchedule) Describe() string {
+	at := fmt.Sprintf("at %02d:%02d %s", ds.hour, ds.minute, ds.location)
+
+	switch ds.descriptor {
+	case weekly:
+		return fmt.Sprintf("every week on %s %s", ds.at(0).Weekday(), at)
+	case monthly:
+		return fmt.Sprintf("every month on day %d %s", ds.day, at)
+	case yearly:
+		return fmt.Sprintf("every year on %d %s %s", ds.day, ds.month, at)
+	default:
+		return fmt.Sprintf("every day %s", at)
+	}
+}
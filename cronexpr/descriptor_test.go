@@ -0,0 +1,86 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		from     time.Time
+		want     []time.Time
+		prev     time.Time
+		describe string
+	}{
+		{
+			name:     "daily",
+			interval: "07:00@daily",
+			from:     time.Date(2024, 2, 28, 8, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 29, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC),
+			},
+			prev:     time.Date(2024, 2, 28, 7, 0, 0, 0, time.UTC),
+			describe: "every day at 07:00 UTC",
+		},
+		{
+			name:     "monthly is anchored to the 1st of the month",
+			interval: "07:00@monthly",
+			from:     time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC),
+			},
+			prev:     time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC),
+			describe: "every month on day 1 at 07:00 UTC",
+		},
+		{
+			name:     "yearly is anchored to 1 January",
+			interval: "07:00@yearly",
+			from:     time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 7, 0, 0, 0, time.UTC),
+			},
+			prev:     time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC),
+			describe: "every year on 1 January at 07:00 UTC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseInLocation(tt.interval, time.UTC)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.NextN(tt.from, len(tt.want)))
+			assert.Equal(t, tt.prev, s.Prev(tt.from))
+			assert.Equal(t, tt.describe, s.Describe())
+		})
+	}
+}
+
+func TestDescriptorDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// 2024-03-10 02:00 EST jumps to 03:00 EDT, the wall clock is kept
+	s, err := ParseInLocation("07:00@daily", ny)
+	assert.NoError(t, err)
+	got := s.NextN(time.Date(2024, 3, 9, 8, 0, 0, 0, ny), 2)
+	assert.Equal(t, 7, got[0].Hour())
+	assert.Equal(t, 23*time.Hour, got[0].Sub(time.Date(2024, 3, 9, 7, 0, 0, 0, ny)))
+
+	// the skipped wall clock is run right after the transition
+	s, err = ParseInLocation("02:30@daily", ny)
+	assert.NoError(t, err)
+	got = s.NextN(time.Date(2024, 3, 9, 8, 0, 0, 0, ny), 2)
+	assert.True(t, time.Date(2024, 3, 10, 3, 30, 0, 0, ny).Equal(got[0]), got[0])
+	assert.True(t, time.Date(2024, 3, 11, 2, 30, 0, 0, ny).Equal(got[1]), got[1])
+	assert.True(t, got[0].Equal(s.Prev(got[1])))
+}
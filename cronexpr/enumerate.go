@@ -0,0 +1,84 @@
+package cronexpr
+
+import "time"
+
+const (
+	// maxEnumerate maximum times returned by NextN and Between
+	maxEnumerate = 10000
+	// maxDSTProbe maximum minutes to probe when the wall clock is repeated on DST transition
+	maxDSTProbe = 24 * 60
+	// maxPrevLookup maximum range to look up the previous time
+	maxPrevLookup = 200 * 365 * oneDay
+)
+
+// prev returns the closest time before fromTime, only use Next of the schedule.
+// search the lower bound by doubling the range, then binary search the latest time before fromTime
+func prev(s Schedule, fromTime time.Time) time.Time {
+	if fromTime.IsZero() {
+		return fromTime
+	}
+
+	before := func(t time.Time) bool {
+		return !t.IsZero() && t.Before(fromTime)
+	}
+
+	// find lower bound which the next time is before fromTime
+	var lo time.Time
+	for window := time.Minute; ; window *= 2 {
+		if window > maxPrevLookup {
+			return time.Time{}
+		}
+
+		lo = fromTime.Add(-window)
+		if before(s.Next(lo)) {
+			break
+		}
+	}
+
+	// the latest lower bound which the next time still before fromTime
+	hi := fromTime
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if before(s.Next(mid)) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return s.Next(lo)
+}
+
+// nextInterval returns duration until the next time, 0 when the schedule has no next time
+func nextInterval(s Schedule, fromTime time.Time) time.Duration {
+	next := s.Next(fromTime)
+	if next.IsZero() {
+		return 0
+	}
+
+	return next.Sub(fromTime)
+}
+
+// nextN returns the next n times after fromTime
+func nextN(s Schedule, fromTime time.Time, n int) []time.Time {
+	if n > maxEnumerate {
+		n = maxEnumerate
+	}
+
+	var times []time.Time
+	for next := s.Next(fromTime); !next.IsZero() && len(times) < n; next = s.Next(next) {
+		times = append(times, next)
+	}
+
+	return times
+}
+
+// between returns all times after from until to (inclusive)
+func between(s Schedule, from, to time.Time) []time.Time {
+	var times []time.Time
+	for next := s.Next(from); !next.IsZero() && !next.After(to) && len(times) < maxEnumerate; next = s.Next(next) {
+		times = append(times, next)
+	}
+
+	return times
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"sort"
 	"time"
+
+	"github.com/mqdvi-dp/go-common/zone"
 )
 
 // An Expression represents a specific cron time expression as defined at
@@ -49,7 +51,23 @@ func MustParse(cronLine string) Schedule {
 // See <https://github.com/gorhill/cronexpr#implementation> for documentation
 // about what is a well-formed cron expression from this library's point of
 // view.
+//
+// Descriptor businessday (e.g.: 07:00@businessday) returns a schedule which runs
+// at the given time from monday to friday and skips the holidays of DefaultCalendar.
+//
+// Descriptors daily and weekly (e.g.: 07:00@weekly) returns a schedule which starts
+// at the closest given time in Asia/Jakarta and repeats with calendar period.
+// Descriptors monthly and yearly run at the given time on the 1st of the month and 1 January.
 func Parse(cronLine string) (Schedule, error) {
+	return ParseInLocation(cronLine, zone.TzJakarta())
+}
+
+// ParseInLocation like Parse but the descriptor (e.g.: 07:00@monthly) is interpreted in the given location
+func ParseInLocation(cronLine string, loc *time.Location) (Schedule, error) {
+	// Maybe one of the calendar descriptors is being used
+	if schedule, ok, err := parseDescriptor(cronLine, loc); ok {
+		return schedule, err
+	}
 
 	// Maybe one of the built-in aliases is being used
 	cron := cronNormalizer.Replace(cronLine)
@@ -136,6 +154,59 @@ func Parse(cronLine string) (Schedule, error) {
 // The zero value of time.Time is returned if no matching time instant exists
 // or if a `fromTime` is itself a zero value.
 func (expr *expression) Next(fromTime time.Time) time.Time {
+	next := expr.next(fromTime)
+
+	// On DST transition (the skipped or the repeated hour), the wall clock of
+	// the matching time instant may be resolved before `fromTime` or outside of
+	// the expression (the skipped wall clock). Scan the wall clock minute by
+	// minute to find the matching time instant, the skipped wall clock is not run.
+	if !next.IsZero() && (!next.After(fromTime) || !expr.matchMinute(next)) {
+		if t := expr.scan(fromTime); !t.IsZero() {
+			return t
+		}
+
+		// no matching wall clock in the scanned minutes, continue after them
+		return expr.Next(fromTime.Truncate(time.Minute).Add(maxDSTProbe*time.Minute - time.Nanosecond))
+	}
+
+	return next
+}
+
+// scan returns the first time instant after `fromTime` which matches the cron
+// expression by checking the wall clock of every minute.
+func (expr *expression) scan(fromTime time.Time) time.Time {
+	probe := fromTime.Truncate(time.Minute)
+	for i := 0; i < maxDSTProbe; i++ {
+		if expr.matchMinute(probe) {
+			for _, sec := range expr.secondList {
+				if t := probe.Add(time.Duration(sec) * time.Second); t.After(fromTime) {
+					return t
+				}
+			}
+		}
+
+		probe = probe.Add(time.Minute)
+	}
+
+	return time.Time{}
+}
+
+// matchMinute returns true when the wall clock of `t` matches the cron
+// expression, regardless of the second.
+func (expr *expression) matchMinute(t time.Time) bool {
+	return contains(expr.yearList, t.Year()) &&
+		contains(expr.monthList, int(t.Month())) &&
+		contains(expr.calculateActualDaysOfMonth(t.Year(), int(t.Month())), t.Day()) &&
+		contains(expr.hourList, t.Hour()) &&
+		contains(expr.minuteList, t.Minute())
+}
+
+func contains(list []int, v int) bool {
+	i := sort.SearchInts(list, v)
+	return i < len(list) && list[i] == v
+}
+
+func (expr *expression) next(fromTime time.Time) time.Time {
 	// Special case
 	if fromTime.IsZero() {
 		return fromTime
@@ -217,5 +288,21 @@ func (expr *expression) Next(fromTime time.Time) time.Time {
 
 // NextInterval method
 func (expr *expression) NextInterval(fromTime time.Time) time.Duration {
-	return expr.Next(fromTime).Sub(fromTime)
+	return nextInterval(expr, fromTime)
+}
+
+// Prev returns the closest time instant immediately before `fromTime` which
+// matches the cron expression `expr`.
+func (expr *expression) Prev(fromTime time.Time) time.Time {
+	return prev(expr, fromTime)
+}
+
+// NextN returns the next `n` time instants following `fromTime`.
+func (expr *expression) NextN(fromTime time.Time, n int) []time.Time {
+	return nextN(expr, fromTime, n)
+}
+
+// Between returns all time instants after `from` until `to` (inclusive).
+func (expr *expression) Between(from, to time.Time) []time.Time {
+	return between(expr, from, to)
 }
@@ -0,0 +1,96 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpressionPrev(t *testing.T) {
+	s := MustParse("0 9 * * *")
+
+	assert.Equal(t, time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC), s.Prev(time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)))
+	// the previous time is strictly before the given time
+	assert.Equal(t, time.Date(2024, 1, 9, 9, 0, 0, 0, time.UTC), s.Prev(time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, s.Prev(time.Time{}).IsZero())
+}
+
+func TestExpressionNextN(t *testing.T) {
+	s := MustParse("*/15 * * * *")
+	from := time.Date(2024, 1, 1, 10, 7, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC),
+	}, s.NextN(from, 3))
+	assert.Len(t, s.NextN(from, maxEnumerate+1), maxEnumerate)
+
+	// between is inclusive of the end
+	assert.Equal(t, s.NextN(from, 3), s.Between(from, time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC)))
+}
+
+func TestExpressionDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			// 2024-03-10 02:00 EST jumps to 03:00 EDT
+			name: "the skipped wall clock is not run",
+			expr: "30 2 * * *",
+			from: time.Date(2024, 3, 9, 3, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2024, 3, 11, 2, 30, 0, 0, ny),
+				time.Date(2024, 3, 12, 2, 30, 0, 0, ny),
+			},
+		},
+		{
+			name: "the runs after the skipped wall clock on the same day",
+			expr: "30 2,4 * * *",
+			from: time.Date(2024, 3, 9, 3, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2024, 3, 9, 4, 30, 0, 0, ny),
+				time.Date(2024, 3, 10, 4, 30, 0, 0, ny),
+				time.Date(2024, 3, 11, 2, 30, 0, 0, ny),
+			},
+		},
+		{
+			name: "hourly skips the missing hour",
+			expr: "0 * * * *",
+			from: time.Date(2024, 3, 10, 0, 30, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2024, 3, 10, 1, 0, 0, 0, ny),
+				time.Date(2024, 3, 10, 3, 0, 0, 0, ny),
+			},
+		},
+		{
+			// 2024-11-03 02:00 EDT goes back to 01:00 EST
+			name: "the repeated wall clock is run once",
+			expr: "30 1 * * *",
+			from: time.Date(2024, 11, 3, 0, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 1, 30, 0, 0, ny),
+				time.Date(2024, 11, 4, 1, 30, 0, 0, ny),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParse(tt.expr).NextN(tt.from, len(tt.want))
+			assert.Len(t, got, len(tt.want))
+			for i := range tt.want {
+				assert.True(t, tt.want[i].Equal(got[i]), "want %s, got %s", tt.want[i], got[i])
+			}
+		})
+	}
+}
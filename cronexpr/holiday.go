@@ -0,0 +1,149 @@
+package cronexpr
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/zone"
+)
+
+// indonesiaHolidays national holidays (libur nasional) based on SKB 3 Menteri, excluding cuti bersama
+var indonesiaHolidays = map[string]string{
+	// 2024
+	"2024-01-01": "Tahun Baru Masehi",
+	"2024-02-08": "Isra Mi'raj Nabi Muhammad SAW",
+	"2024-02-10": "Tahun Baru Imlek",
+	"2024-03-11": "Hari Suci Nyepi",
+	"2024-03-29": "Wafat Yesus Kristus",
+	"2024-03-31": "Hari Paskah",
+	"2024-04-10": "Hari Raya Idul Fitri",
+	"2024-04-11": "Hari Raya Idul Fitri",
+	"2024-05-01": "Hari Buruh Internasional",
+	"2024-05-09": "Kenaikan Yesus Kristus",
+	"2024-05-23": "Hari Raya Waisak",
+	"2024-06-01": "Hari Lahir Pancasila",
+	"2024-06-17": "Hari Raya Idul Adha",
+	"2024-07-07": "Tahun Baru Islam",
+	"2024-08-17": "Hari Kemerdekaan Republik Indonesia",
+	"2024-09-16": "Maulid Nabi Muhammad SAW",
+	"2024-12-25": "Hari Raya Natal",
+	// 2025
+	"2025-01-01": "Tahun Baru Masehi",
+	"2025-01-27": "Isra Mi'raj Nabi Muhammad SAW",
+	"2025-01-29": "Tahun Baru Imlek",
+	"2025-03-29": "Hari Suci Nyepi",
+	"2025-03-31": "Hari Raya Idul Fitri",
+	"2025-04-01": "Hari Raya Idul Fitri",
+	"2025-04-18": "Wafat Yesus Kristus",
+	"2025-04-20": "Hari Paskah",
+	"2025-05-01": "Hari Buruh Internasional",
+	"2025-05-12": "Hari Raya Waisak",
+	"2025-05-29": "Kenaikan Yesus Kristus",
+	"2025-06-01": "Hari Lahir Pancasila",
+	"2025-06-06": "Hari Raya Idul Adha",
+	"2025-06-27": "Tahun Baru Islam",
+	"2025-08-17": "Hari Kemerdekaan Republik Indonesia",
+	"2025-09-05": "Maulid Nabi Muhammad SAW",
+	"2025-12-25": "Hari Raya Natal",
+	// 2026
+	"2026-01-01": "Tahun Baru Masehi",
+	"2026-01-16": "Isra Mi'raj Nabi Muhammad SAW",
+	"2026-02-17": "Tahun Baru Imlek",
+	"2026-03-19": "Hari Suci Nyepi",
+	"2026-03-20": "Hari Raya Idul Fitri",
+	"2026-03-21": "Hari Raya Idul Fitri",
+	"2026-04-03": "Wafat Yesus Kristus",
+	"2026-04-05": "Hari Paskah",
+	"2026-05-01": "Hari Buruh Internasional",
+	"2026-05-14": "Kenaikan Yesus Kristus",
+	"2026-05-27": "Hari Raya Idul Adha",
+	"2026-05-31": "Hari Raya Waisak",
+	"2026-06-01": "Hari Lahir Pancasila",
+	"2026-06-16": "Tahun Baru Islam",
+	"2026-08-17": "Hari Kemerdekaan Republik Indonesia",
+	"2026-08-25": "Maulid Nabi Muhammad SAW",
+	"2026-12-25": "Hari Raya Natal",
+}
+
+// IndonesiaHolidays returns built-in Indonesian national holidays
+//
+// cuti bersama and bank holidays are not included, add them with HolidaysFromFile or HolidaysFromEnv
+func IndonesiaHolidays() []Holiday {
+	holidays := make([]Holiday, 0, len(indonesiaHolidays))
+	for date, name := range indonesiaHolidays {
+		h, err := parseHoliday(date, name)
+		if err != nil {
+			continue
+		}
+
+		holidays = append(holidays, h)
+	}
+
+	return holidays
+}
+
+// HolidaysFromFile load holidays from file
+//
+// every line contains the date (YYYY-MM-DD) and optionally followed by the name, separated by comma.
+// empty line and line started with # are ignored, e.g.:
+//
+//	# bank holidays
+//	2025-12-31,Libur Bank
+func HolidaysFromFile(path string) ([]Holiday, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var holidays []Holiday
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		date, name, _ := strings.Cut(line, ",")
+		h, err := parseHoliday(date, name)
+		if err != nil {
+			return nil, err
+		}
+
+		holidays = append(holidays, h)
+	}
+
+	return holidays, scanner.Err()
+}
+
+// HolidaysFromEnv load holidays from env variable with list of date (YYYY-MM-DD) separated by comma
+func HolidaysFromEnv(key string) ([]Holiday, error) {
+	var holidays []Holiday
+	for _, date := range env.GetListString(key) {
+		if strings.TrimSpace(date) == "" {
+			continue
+		}
+
+		h, err := parseHoliday(date, "")
+		if err != nil {
+			return nil, err
+		}
+
+		holidays = append(holidays, h)
+	}
+
+	return holidays, nil
+}
+
+func parseHoliday(date, name string) (Holiday, error) {
+	t, err := time.ParseInLocation(layoutDate, strings.TrimSpace(date), zone.TzJakarta())
+	if err != nil {
+		return Holiday{}, fmt.Errorf("invalid holiday date %s. must be YYYY-MM-DD", date)
+	}
+
+	return Holiday{Date: t, Name: strings.TrimSpace(name)}, nil
+}
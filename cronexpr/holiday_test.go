@@ -0,0 +1,73 @@
+package cronexpr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/zone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHoliday(t *testing.T) {
+	h, err := parseHoliday(" 2025-12-31 ", " Libur Bank ")
+	assert.NoError(t, err)
+	assert.Equal(t, Holiday{Date: time.Date(2025, 12, 31, 0, 0, 0, 0, zone.TzJakarta()), Name: "Libur Bank"}, h)
+
+	_, err = parseHoliday("31-12-2025", "")
+	assert.EqualError(t, err, "invalid holiday date 31-12-2025. must be YYYY-MM-DD")
+}
+
+func TestHolidaysFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "holidays.txt")
+	content := "# bank holidays\n\n2025-12-31,Libur Bank\n 2026-01-02 \n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	holidays, err := HolidaysFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Holiday{
+		{Date: time.Date(2025, 12, 31, 0, 0, 0, 0, zone.TzJakarta()), Name: "Libur Bank"},
+		{Date: time.Date(2026, 1, 2, 0, 0, 0, 0, zone.TzJakarta())},
+	}, holidays)
+
+	invalid := filepath.Join(dir, "invalid.txt")
+	assert.NoError(t, os.WriteFile(invalid, []byte("2025-12-31\n2025/12/31\n"), 0o600))
+
+	_, err = HolidaysFromFile(invalid)
+	assert.Error(t, err)
+
+	_, err = HolidaysFromFile(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestHolidaysFromEnv(t *testing.T) {
+	t.Setenv("TEST_CRON_HOLIDAYS", "2025-03-31, 2025-04-01,")
+
+	holidays, err := HolidaysFromEnv("TEST_CRON_HOLIDAYS")
+	assert.NoError(t, err)
+	assert.Equal(t, []Holiday{
+		{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, zone.TzJakarta())},
+		{Date: time.Date(2025, 4, 1, 0, 0, 0, 0, zone.TzJakarta())},
+	}, holidays)
+
+	holidays, err = HolidaysFromEnv("TEST_CRON_HOLIDAYS_NOT_SET")
+	assert.NoError(t, err)
+	assert.Empty(t, holidays)
+
+	t.Setenv("TEST_CRON_HOLIDAYS", "2025-03-31,2025-04-31")
+	_, err = HolidaysFromEnv("TEST_CRON_HOLIDAYS")
+	assert.Error(t, err)
+}
+
+func TestIndonesiaHolidays(t *testing.T) {
+	holidays := IndonesiaHolidays()
+	assert.Len(t, holidays, len(indonesiaHolidays))
+
+	cal := NewCalendar(holidays...)
+	assert.True(t, cal.IsHoliday(time.Date(2025, 8, 17, 7, 0, 0, 0, zone.TzJakarta())))
+	assert.True(t, cal.IsHoliday(time.Date(2026, 3, 20, 7, 0, 0, 0, zone.TzJakarta())))
+	assert.False(t, cal.IsHoliday(time.Date(2025, 8, 18, 7, 0, 0, 0, zone.TzJakarta())))
+}
@@ -16,8 +16,18 @@ import (
 
 // Schedule abstraction for calculate next interval time
 type Schedule interface {
+	// Next returns the closest time after the given time
 	Next(time.Time) time.Time
+	// NextInterval returns duration until the next time, 0 when there is no next time
 	NextInterval(time.Time) time.Duration
+	// Prev returns the closest time before the given time
+	Prev(time.Time) time.Time
+	// NextN returns the next n times after the given time
+	NextN(time.Time, int) []time.Time
+	// Between returns all times after from until to (inclusive), limited to maxEnumerate
+	Between(from, to time.Time) []time.Time
+	// Describe returns human-readable schedule
+	Describe() string
 }
 
 var (
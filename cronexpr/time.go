@@ -16,9 +16,9 @@ const (
 	oneDay = 24 * time.Hour
 	// oneWeek const
 	oneWeek = 7 * oneDay
-	// oneMonth const
+	// oneMonth const, approximate only. the schedule of monthly descriptor use calendar arithmetic
 	oneMonth = 30 * oneDay
-	// oneYear const
+	// oneYear const, approximate only. the schedule of yearly descriptor use calendar arithmetic
 	oneYear = 12 * oneMonth
 
 	// daily const
@@ -29,13 +29,29 @@ const (
 	monthly = "monthly"
 	// yearly const
 	yearly = "yearly"
+	// businessday const
+	businessday = "businessday"
 )
 
 const (
 	layoutTime = "15:04"
 )
 
+// ParseDuration parse interval with Asia/Jakarta as the default location
+//
+// the next duration of calendar descriptors (daily, weekly, monthly, yearly. e.g.: 07:00@monthly) is approximate,
+// a month is 30 days and a year is 360 days, so the run drifts from the calendar and on DST transition.
+// the calendar descriptors of ParseDuration are deprecated, use Parse to get the schedule with calendar arithmetic
+//
+// descriptor businessday (e.g.: 07:00@businessday) returns the duration until the next business day
+// of DefaultCalendar and one day as the next duration, the caller must skip the run which is not a business day
+// or use Parse to get the exact schedule
 func ParseDuration(t string) (duration, nextDuration time.Duration, err error) {
+	return ParseDurationInLocation(t, zone.TzJakarta())
+}
+
+// ParseDurationInLocation like ParseDuration but the start time (HH:mm) is interpreted in the given location
+func ParseDurationInLocation(t string, loc *time.Location) (duration, nextDuration time.Duration, err error) {
 	interval, err := time.ParseDuration(t)
 	if err == nil {
 		return interval, 0, nil
@@ -48,6 +64,10 @@ func ParseDuration(t string) (duration, nextDuration time.Duration, err error) {
 		return 0, 0, errors.New("time format error. must be HH:mm")
 	}
 
+	if loc == nil {
+		loc = zone.TzJakarta()
+	}
+
 	repeat := oneMinute
 	if len(delimiter) > 1 {
 		switch strings.ToLower(delimiter[1]) {
@@ -59,28 +79,31 @@ func ParseDuration(t string) (duration, nextDuration time.Duration, err error) {
 			repeat = oneMonth
 		case yearly:
 			repeat = oneYear
+		case businessday:
+			schedule, _, err := parseDescriptor(t, loc)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			return schedule.NextInterval(time.Now().In(loc)), oneDay, nil
 		default:
 			repeat, err = time.ParseDuration(delimiter[1])
 			if err != nil {
 				return 0, 0, fmt.Errorf(
-					`invalid descriptor "%s". Must One of ("daily", "weekly", "monthly", "yearly") or duration string`,
+					`invalid descriptor "%s". Must One of ("daily", "weekly", "monthly", "yearly", "businessday") or duration string`,
 					delimiter[1],
 				)
 			}
 		}
 	}
 
-	now := time.Now().In(zone.TzJakarta())
+	now := time.Now().In(loc)
 	atTime := time.Date(now.Year(), now.Month(), now.Day(), ts.Hour(), ts.Minute(), 0, 0, now.Location())
-	if now.Before(atTime) {
-		duration = atTime.Sub(now)
-	} else {
-		duration = oneDay - now.Sub(atTime)
-	}
-
-	if duration < 0 {
-		duration *= -1
+	if !now.Before(atTime) {
+		// use AddDate instead of 24 hours, the day may be shorter or longer on DST transition
+		atTime = atTime.AddDate(0, 0, 1)
 	}
+	duration = atTime.Sub(now)
 
 	nextDuration = repeat
 
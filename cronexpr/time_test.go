@@ -0,0 +1,41 @@
+package cronexpr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		interval     string
+		nextDuration time.Duration
+		wantErr      bool
+	}{
+		{name: "duration", interval: "5m"},
+		{name: "daily", interval: "07:00@daily", nextDuration: 24 * time.Hour},
+		{name: "weekly", interval: "07:00@weekly", nextDuration: 7 * 24 * time.Hour},
+		{name: "monthly is 30 days", interval: "07:00@monthly", nextDuration: 30 * 24 * time.Hour},
+		{name: "yearly is 360 days", interval: "07:00@yearly", nextDuration: 360 * 24 * time.Hour},
+		{name: "repeat duration", interval: "07:00@10s", nextDuration: 10 * time.Second},
+		{name: "invalid descriptor", interval: "07:00@hourly", wantErr: true},
+		{name: "invalid time", interval: "7am@daily", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration, nextDuration, err := ParseDuration(tt.interval)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Greater(t, duration, time.Duration(0))
+			assert.LessOrEqual(t, duration, 24*time.Hour)
+			assert.Equal(t, tt.nextDuration, nextDuration)
+		})
+	}
+}
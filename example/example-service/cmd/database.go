@@ -26,5 +26,10 @@ func sqlDatabase() (abstract.SQLDatabase, abstract.SQLDatabase, abstract.SQLData
 }
 
 func redisDatabase() abstract.RedisDatabase {
-	return database.NewRedisConnection(database.SetRedisAddress([]string{"127.0.0.1:6379"}))
+	rds, err := database.NewRedisConnection(database.SetRedisAddress([]string{"127.0.0.1:6379"}))
+	if err != nil {
+		panic(err)
+	}
+
+	return rds
 }
@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/mqdvi-dp/go-common/abstract"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/constants"
+	"github.com/mqdvi-dp/go-common/factory/server/redisstream"
+	"github.com/mqdvi-dp/go-common/logger"
+)
+
+// redisStreamBroker configuration
+type redisStreamBroker struct {
+	client *redisstream.Client
+}
+
+// NewRedisStreamBroker setup redis streams broker for publisher and worker,
+// the publisher append the message into the stream with topic as the stream name, e.g.:
+//
+//	publisher.PublishMessage(ctx, &types.PublisherArgument{
+//		Topic:   "order-created",
+//		Key:     orderId, // optional
+//		Message: payload,
+//	})
+func NewRedisStreamBroker(client rdc.Rdc, opts ...redisstream.ClientOptionFunc) abstract.Broker {
+	logger.PurpleItalic("Load redis stream broker...")
+	if client == nil {
+		panic("redis client for redis stream broker cannot be nil")
+	}
+
+	return &redisStreamBroker{client: redisstream.NewClient(client, opts...)}
+}
+
+func (rb *redisStreamBroker) GetConfiguration() interface{} {
+	return rb.client
+}
+
+func (rb *redisStreamBroker) GetPublisher() abstract.Publisher {
+	return rb.client
+}
+
+func (rb *redisStreamBroker) GetName() constants.Worker {
+	return constants.RedisStream
+}
+
+// Disconnect do nothing, the redis connection is closed by the redis database dependency
+func (rb *redisStreamBroker) Disconnect(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,53 @@
+package broker
+
+import (
+	"context"
+
+	"github.com/mqdvi-dp/go-common/abstract"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/constants"
+	"github.com/mqdvi-dp/go-common/factory/server/redistask"
+	"github.com/mqdvi-dp/go-common/logger"
+)
+
+// redisTaskBroker configuration
+type redisTaskBroker struct {
+	client *redistask.Client
+}
+
+// NewRedisTaskBroker setup redis delayed task broker for publisher and worker,
+// the publisher enqueue the task with topic as the task name, e.g.:
+//
+//	publisher.PublishMessage(ctx, &types.PublisherArgument{
+//		Topic:   "expire-order",
+//		Key:     orderId, // unique key, optional
+//		Header:  map[string]interface{}{redistask.HeaderDelay: 15 * time.Minute},
+//		Message: payload,
+//	})
+//
+// use GetConfiguration().(*redistask.Client) to cancel the task by id
+func NewRedisTaskBroker(client rdc.Rdc, opts ...redistask.ClientOptionFunc) abstract.Broker {
+	logger.PurpleItalic("Load redis task broker...")
+	if client == nil {
+		panic("redis client for redis task broker cannot be nil")
+	}
+
+	return &redisTaskBroker{client: redistask.NewClient(client, opts...)}
+}
+
+func (rb *redisTaskBroker) GetConfiguration() interface{} {
+	return rb.client
+}
+
+func (rb *redisTaskBroker) GetPublisher() abstract.Publisher {
+	return rb.client
+}
+
+func (rb *redisTaskBroker) GetName() constants.Worker {
+	return constants.RedisTask
+}
+
+// Disconnect do nothing, the redis connection is closed by the redis database dependency
+func (rb *redisTaskBroker) Disconnect(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/logger"
+)
+
+const (
+	triggerSchedule = "schedule"
+	triggerSuccess  = "success"
+	triggerFailure  = "failure"
+)
+
+// chainRun is the information of a job run in the chain
+type chainRun struct {
+	chainId     string
+	trigger     string
+	triggeredBy string
+}
+
+// newChainRun start a new chain from a scheduled job
+func newChainRun() chainRun {
+	return chainRun{chainId: uuid.NewString(), trigger: triggerSchedule}
+}
+
+// log returns the chain status for the log of the run
+func (r chainRun) log() *logger.Chain {
+	return &logger.Chain{ChainId: r.chainId, Trigger: r.trigger, TriggeredBy: r.triggeredBy}
+}
+
+// linkJobs connect every triggered job to the upstream jobs,
+// returns error when the upstream job is missing or the dependency graph has a cycle
+func (c *cronWorker) linkJobs() error {
+	jobs := make(map[string]*job, len(c.jobs))
+	duplicates := make(map[string]bool)
+	for _, j := range c.jobs {
+		if _, ok := jobs[j.handlerName]; ok {
+			duplicates[j.handlerName] = true
+		}
+		jobs[j.handlerName] = j
+	}
+
+	for _, j := range c.jobs {
+		j.satisfied = make(map[string]bool)
+		j.after = unique(j.after)
+		j.onFailure = unique(j.onFailure)
+		for _, name := range append(append([]string{}, j.after...), j.onFailure...) {
+			if duplicates[name] {
+				return fmt.Errorf("job '%s' depends on job '%s' which registered more than once", j.handlerName, name)
+			}
+		}
+
+		for _, name := range j.after {
+			upstream, ok := jobs[name]
+			if !ok {
+				return fmt.Errorf("job '%s' depends on unknown job '%s'", j.handlerName, name)
+			}
+			upstream.successors = append(upstream.successors, j)
+		}
+
+		for _, name := range j.onFailure {
+			upstream, ok := jobs[name]
+			if !ok {
+				return fmt.Errorf("job '%s' handles failure of unknown job '%s'", j.handlerName, name)
+			}
+			upstream.failureHandlers = append(upstream.failureHandlers, j)
+		}
+	}
+
+	// detect cycle with depth first search
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*job]int, len(c.jobs))
+	var visit func(j *job, path []string) error
+	visit = func(j *job, path []string) error {
+		path = append(path, j.handlerName)
+		switch state[j] {
+		case visiting:
+			return fmt.Errorf("job dependency has a cycle: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[j] = visiting
+		for _, next := range append(append([]*job{}, j.successors...), j.failureHandlers...) {
+			if err := visit(next, path); err != nil {
+				return err
+			}
+		}
+		state[j] = visited
+
+		return nil
+	}
+
+	for _, j := range c.jobs {
+		if err := visit(j, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notify trigger the downstream jobs after the job is done.
+// a job with multiple upstream jobs runs after all of them succeed since its last run,
+// a failed upstream job must succeed again before the job runs
+func (c *cronWorker) notify(j *job, r chainRun, err error) {
+	var ready []*job
+	var status string
+
+	c.chainLock.Lock()
+	if err != nil {
+		status = triggerFailure
+		for _, next := range j.successors {
+			delete(next.satisfied, j.handlerName)
+		}
+		ready = j.failureHandlers
+	} else {
+		status = triggerSuccess
+		for _, next := range j.successors {
+			next.satisfied[j.handlerName] = true
+			if len(next.satisfied) < len(next.after) {
+				continue
+			}
+
+			next.satisfied = make(map[string]bool)
+			ready = append(ready, next)
+		}
+	}
+	c.chainLock.Unlock()
+
+	for _, next := range ready {
+		logger.Yellow(fmt.Sprintf("cron job > job %s %s, trigger job %s (chain_id: %s)", j.handlerName, status, next.handlerName, r.chainId))
+		c.dispatch(next, chainRun{chainId: r.chainId, trigger: status, triggeredBy: j.handlerName})
+	}
+}
+
+// unique remove the duplicate job names
+func unique(names []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
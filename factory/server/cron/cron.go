@@ -2,7 +2,9 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"reflect"
 	"sync"
@@ -20,6 +22,9 @@ import (
 
 const lockPattern = "%s:lock-cron-worker:%s"
 
+// errJobLocked the job is running on another instance
+var errJobLocked = errors.New("job is locked")
+
 type cronWorker struct {
 	ctx                          context.Context
 	cancelFunc                   func()
@@ -28,9 +33,13 @@ type cronWorker struct {
 	service                      factory.ServiceFactory
 	workers                      []reflect.SelectCase
 	refreshWorkerNotif, shutdown chan struct{}
+	stopping                     chan struct{}
+	stopOnce                     sync.Once
 	semaphore                    []chan struct{}
 	wg                           sync.WaitGroup
 	activeJobs                   []*job
+	jobs                         []*job
+	chainLock                    sync.Mutex
 }
 
 // NewWorker create new cron worker
@@ -41,6 +50,7 @@ func NewWorker(service factory.ServiceFactory, opts ...OptionFunc) factory.AppSe
 		tz:                 zone.TzJakarta(),
 		refreshWorkerNotif: make(chan struct{}),
 		shutdown:           make(chan struct{}),
+		stopping:           make(chan struct{}),
 	}
 
 	for _, opt := range opts {
@@ -73,24 +83,43 @@ func NewWorker(service factory.ServiceFactory, opts ...OptionFunc) factory.AppSe
 			if handler.Pattern == "" {
 				logger.Log.Fatal("cron pattern not yet set. please set the pattern using, types.WorkerHandlerOptionPattern(cron.CreateSchedulerKey(param))")
 			}
-			funcName, interval := ParseSchedulerKey(handler.Pattern)
+			sk := parseSchedulerKey(handler.Pattern)
 
 			j := job{
-				handlerName: funcName,
-				handler:     handler,
-				interval:    interval,
+				handlerName:   sk.JobName,
+				handler:       handler,
+				interval:      sk.Interval,
+				timezone:      sk.Timezone,
+				jitter:        sk.Jitter,
+				overlap:       sk.Overlap,
+				maxConcurrent: sk.MaxConcurrent,
+				maxRuntime:    sk.MaxRuntime,
+				modifier:      sk.Modifier,
+				after:         sk.After,
+				onFailure:     sk.OnFailure,
 			}
 
 			if err := c.addJob(&j); err != nil {
-				logger.Log.Fatalf("Cron Scheduler Worker: '%s' (interval: %s) %s", funcName, interval, err)
+				logger.Log.Fatalf("Cron Scheduler Worker: '%s' (interval: %s) %s", sk.JobName, sk.Interval, err)
 			}
 
-			c.semaphore = append(c.semaphore, make(chan struct{}, c.opt.maxGoroutines))
-			logger.Yellow(fmt.Sprintf(`⇨ [CRON-WORKER] (job name): "%s" (every): %-8s`, funcName, interval))
+			j.semaphore = make(chan struct{}, j.maxConcurrent)
+			c.semaphore = append(c.semaphore, j.semaphore)
+			c.jobs = append(c.jobs, &j)
+			if j.isTriggered() {
+				logger.Yellow(fmt.Sprintf(`⇨ [CRON-WORKER] (job name): "%s" (after): %v (on failure): %v (overlap): %s`, j.handlerName, j.after, j.onFailure, j.overlap))
+				continue
+			}
+			logger.Yellow(fmt.Sprintf(`⇨ [CRON-WORKER] (job name): "%s" (every): %-8s (tz): %s (overlap): %s`, j.handlerName, j.interval, j.location, j.overlap))
 		}
 	}
 
-	fmt.Printf("\x1b[34;1m⇨ Cron worker running with %d jobs\x1b[0m\n\n", len(c.activeJobs))
+	// validate the job dependencies before running any job
+	if err := c.linkJobs(); err != nil {
+		logger.Log.Fatalf("Cron Scheduler Worker: %s", err)
+	}
+
+	fmt.Printf("\x1b[34;1m⇨ Cron worker running with %d jobs\x1b[0m\n\n", len(c.jobs))
 	c.ctx, c.cancelFunc = context.WithCancel(context.Background())
 	return c
 }
@@ -125,26 +154,85 @@ func (c *cronWorker) Serve() {
 		j := c.activeJobs[chosen]
 		c.registerNextInterval(j)
 
-		if len(c.semaphore[j.workerIndex-2]) >= c.opt.maxGoroutines {
-			continue
-		}
+		c.dispatch(j, newChainRun())
+	}
+}
+
+// dispatch run the job based on the overlap policy
+func (c *cronWorker) dispatch(j *job, r chainRun) {
+	if c.isStopping() {
+		logger.Yellow(fmt.Sprintf("cron job > job %s skipped, the worker is shutting down", j.handlerName))
+		return
+	}
 
-		c.semaphore[j.workerIndex-2] <- struct{}{}
+	select {
+	case j.semaphore <- struct{}{}:
 		c.wg.Add(1)
-		go func(j *job) {
-			defer func() {
-				c.wg.Done()
-				<-c.semaphore[j.workerIndex-2]
+		go c.runJob(j, r)
+	default:
+		// when the overlap policy is queue, keep a single pending run
+		// and execute it after the current run is done
+		if j.overlap != OverlapQueue {
+			logger.Yellow(fmt.Sprintf("cron job > job %s still running, skipped", j.handlerName))
+			return
+		}
+
+		select {
+		case j.pending <- struct{}{}:
+			c.wg.Add(1)
+			go func() {
+				select {
+				case j.semaphore <- struct{}{}:
+				case <-c.stopping:
+					// drop the queued run on shutdown
+					<-j.pending
+					c.wg.Done()
+					return
+				}
+
+				<-j.pending
+				c.runJob(j, r)
 			}()
+		default:
+			logger.Yellow(fmt.Sprintf("cron job > job %s already queued, skipped", j.handlerName))
+		}
+	}
+}
 
-			if c.ctx.Err() != nil {
-				logger.Red(fmt.Sprintf("cron_scheduler > context root err: %s", c.ctx.Err()))
-				return
-			}
+// runJob execute the job and trigger the downstream jobs, the semaphore must be acquired by the caller
+func (c *cronWorker) runJob(j *job, r chainRun) {
+	defer func() {
+		c.wg.Done()
+		<-j.semaphore
+	}()
+
+	if c.ctx.Err() != nil {
+		logger.Red(fmt.Sprintf("cron_scheduler > context root err: %s", c.ctx.Err()))
+		return
+	}
+	if c.isStopping() {
+		return
+	}
+
+	// delay the start of the job, avoid all services running at the same time
+	if j.jitter > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(j.jitter))))
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-c.stopping:
+			return
+		}
+	}
 
-			c.processJob(j)
-		}(j)
+	err := c.processJob(j, r)
+	if errors.Is(err, errJobLocked) {
+		// the job is running on another instance, the downstream jobs are triggered there
+		return
 	}
+
+	c.notify(j, r, err)
 }
 
 func (c *cronWorker) Shutdown(_ context.Context) {
@@ -157,6 +245,8 @@ func (c *cronWorker) Shutdown(_ context.Context) {
 	}
 
 	c.stopAllJob()
+	// no more job will start, the runs waiting for the jitter or queued are dropped
+	c.stopOnce.Do(func() { close(c.stopping) })
 	c.shutdown <- struct{}{}
 	runningJob := 0
 	for _, sem := range c.semaphore {
@@ -172,15 +262,31 @@ func (c *cronWorker) Shutdown(_ context.Context) {
 	c.opt.locker.Reset(fmt.Sprintf(lockPattern, c.service.Name(), "*"))
 }
 
-func (c *cronWorker) processJob(j *job) {
-	var err error
-	start := time.Now().In(c.tz)
+// isStopping returns true when the worker is shutting down
+func (c *cronWorker) isStopping() bool {
+	select {
+	case <-c.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *cronWorker) processJob(j *job, r chainRun) (err error) {
+	start := time.Now().In(j.location)
 	ctx := c.ctx
 
+	// cancel the job context when the job running too long
+	if j.maxRuntime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.maxRuntime)
+		defer cancel()
+	}
+
 	// lock for multiple worker (if running on multiple pods/instance)
 	if c.opt.locker.IsLocked(c.getLockKey(j.handlerName)) {
 		logger.Yellow(fmt.Sprintf("cron job > job %s is locked", j.handlerName))
-		return
+		return errJobLocked
 	}
 	defer c.opt.locker.Unlock(c.getLockKey(j.handlerName))
 
@@ -191,6 +297,7 @@ func (c *cronWorker) processJob(j *job) {
 		HandlerType: logger.Scheduler,
 		Service:     c.service.Name(),
 		Endpoint:    fmt.Sprintf("CRON %s", j.handlerName),
+		Chain:       r.log(),
 	}
 
 	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("CronScheduler:%s", j.handlerName))
@@ -215,6 +322,9 @@ func (c *cronWorker) processJob(j *job) {
 		ol.Finalize(ctx)
 	}()
 	trace.SetTag("job_name", j.handlerName)
+	trace.SetTag("chain_id", r.chainId)
+	trace.SetTag("trigger", r.trigger)
+	trace.SetTag("triggered_by", r.triggeredBy)
 
 	// implement locking logging stdout
 	var lock = logger.NewLocker(ctx)
@@ -228,18 +338,36 @@ func (c *cronWorker) processJob(j *job) {
 	ec.SetWorkerType(string(constants.Scheduler))
 	ec.SetTopic(j.handlerName)
 	ec.SetKey(j.handlerName)
-	ec.SetHeader(map[string]interface{}{"interval": j.interval})
+	ec.SetHeader(
+		map[string]interface{}{
+			"interval":     j.interval,
+			"timezone":     j.location.String(),
+			"chain_id":     r.chainId,
+			"trigger":      r.trigger,
+			"triggered_by": r.triggeredBy,
+		},
+	)
 
-	if err := j.handler.HandlerFunc(&ec); err != nil {
+	if err = j.handler.HandlerFunc(&ec); err != nil {
 		ec.SetError(err)
 		trace.SetError(err)
 	}
+
+	return
 }
 
 func (c *cronWorker) registerNextInterval(j *job) {
 	if j.schedule != nil {
 		j.ticker.Stop()
-		j.ticker = time.NewTicker(j.schedule.NextInterval(time.Now().In(c.tz)))
+
+		interval := j.schedule.NextInterval(time.Now().In(j.location))
+		if interval <= 0 {
+			// the ticker is stopped, so the job will not run again until the worker restarted
+			logger.Red(fmt.Sprintf("cron job > job %s (interval: %s) has no next run, the job is stopped", j.handlerName, j.interval))
+			return
+		}
+
+		j.ticker = time.NewTicker(interval)
 		c.workers[j.workerIndex].Chan = reflect.ValueOf(j.ticker.C)
 	} else if j.nextDuration != nil {
 		j.ticker.Stop()
@@ -276,14 +404,83 @@ func (c *cronWorker) addJob(j *job) (err error) {
 		return
 	}
 
-	duration, nextDuration, err := cronexpr.ParseDuration(j.interval)
-	if err != nil {
-		j.schedule, err = cronexpr.Parse(j.interval)
+	j.location = c.tz
+	if j.timezone != "" {
+		j.location, err = time.LoadLocation(j.timezone)
 		if err != nil {
 			return
 		}
+	}
+
+	switch j.overlap {
+	case "":
+		// the key without overlap policy keeps the behaviour before the policies existed,
+		// concurrent runs up to CRON_MAX_GOROUTINES, the next runs are skipped.
+		// the scheduler keys stored before are unchanged, set the policy to change it
+		j.overlap = OverlapAllow
+		j.maxConcurrent = c.opt.maxGoroutines
+	case OverlapSkip, OverlapQueue:
+		j.maxConcurrent = 1
+	case OverlapAllow:
+		if j.maxConcurrent < 1 {
+			err = fmt.Errorf("max concurrent must be greater than 0")
+			return
+		}
+	default:
+		err = fmt.Errorf("invalid overlap policy %s", j.overlap)
+		return
+	}
+	j.pending = make(chan struct{}, 1)
+
+	if j.jitter < 0 || j.maxRuntime < 0 {
+		err = fmt.Errorf("jitter and max runtime cannot be negative")
+		return
+	}
+
+	// the job triggered by other jobs has no schedule
+	if j.isTriggered() {
+		if j.interval != "" || j.modifier != "" {
+			err = fmt.Errorf("job triggered by other jobs cannot have interval or calendar modifier")
+		}
+		return
+	}
+
+	// the cron expression and the calendar descriptors (e.g.: 07:00@monthly) use the schedule,
+	// the duration (e.g.: 5m or 07:00@1h) is the fallback
+	var duration, nextDuration time.Duration
+	j.schedule, err = cronexpr.ParseInLocation(j.interval, j.location)
+	if err == nil {
+		duration = j.schedule.NextInterval(time.Now().In(j.location))
+	} else {
+		var durationErr error
+		duration, nextDuration, durationErr = cronexpr.ParseDurationInLocation(j.interval, j.location)
+		if durationErr != nil {
+			return
+		}
+
+		j.schedule, err = nil, nil
+	}
+
+	if j.modifier != "" {
+		switch j.modifier {
+		case cronexpr.SkipHoliday, cronexpr.NextBusinessDay, cronexpr.PreviousBusinessDay:
+		default:
+			err = fmt.Errorf("invalid calendar modifier %s", j.modifier)
+			return
+		}
 
-		duration = j.schedule.NextInterval(time.Now().In(c.tz))
+		if j.schedule == nil {
+			err = fmt.Errorf("calendar modifier only support cron expression interval")
+			return
+		}
+
+		j.schedule = cronexpr.WithCalendar(j.schedule, cronexpr.DefaultCalendar(), j.modifier)
+		duration = j.schedule.NextInterval(time.Now().In(j.location))
+	}
+
+	if duration <= 0 {
+		err = fmt.Errorf("interval %s has no next run", j.interval)
+		return
 	}
 
 	if nextDuration > 0 {
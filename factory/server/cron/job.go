@@ -9,11 +9,31 @@ import (
 
 // job model
 type job struct {
-	handlerName  string
-	interval     string
-	handler      types.WorkerHandler
-	workerIndex  int
-	ticker       *time.Ticker
-	nextDuration *time.Duration
-	schedule     cronexpr.Schedule
+	handlerName   string
+	interval      string
+	timezone      string
+	handler       types.WorkerHandler
+	workerIndex   int
+	ticker        *time.Ticker
+	nextDuration  *time.Duration
+	schedule      cronexpr.Schedule
+	location      *time.Location
+	jitter        time.Duration
+	overlap       OverlapPolicy
+	maxConcurrent int
+	maxRuntime    time.Duration
+	modifier      cronexpr.Modifier
+	pending       chan struct{}
+	semaphore     chan struct{}
+	after         []string
+	onFailure     []string
+	// downstream jobs, triggered when this job succeeds or fails
+	successors, failureHandlers []*job
+	// upstream jobs succeeded since the last run, used for fan-in
+	satisfied map[string]bool
+}
+
+// isTriggered returns true when the job is triggered by other jobs instead of a schedule
+func (j *job) isTriggered() bool {
+	return len(j.after) > 0 || len(j.onFailure) > 0
 }
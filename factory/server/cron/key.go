@@ -1,12 +1,41 @@
 package cron
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/cronexpr"
+)
+
+// OverlapPolicy is the behaviour of a job when the next schedule arrives while the previous run still in progress
+type OverlapPolicy string
+
+const (
+	// OverlapSkip skip the next run when the previous run still in progress
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue wait until the previous run is done, then run once
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapAllow allow up to N concurrent runs.
+	// the job without overlap policy is OverlapAllow with CRON_MAX_GOROUTINES as N
+	OverlapAllow OverlapPolicy = "allow"
+)
 
 type schedulerKey struct {
-	JobName  string `json:"jobName"`
-	Interval string `json:"interval"`
+	JobName       string            `json:"jobName"`
+	Interval      string            `json:"interval"`
+	Timezone      string            `json:"timezone,omitempty"`
+	Jitter        time.Duration     `json:"jitter,omitempty"`
+	Overlap       OverlapPolicy     `json:"overlap,omitempty"`
+	MaxConcurrent int               `json:"maxConcurrent,omitempty"`
+	MaxRuntime    time.Duration     `json:"maxRuntime,omitempty"`
+	Modifier      cronexpr.Modifier `json:"modifier,omitempty"`
+	After         []string          `json:"after,omitempty"`
+	OnFailure     []string          `json:"onFailure,omitempty"`
 }
 
+// SchedulerKeyOptionFunc option func for scheduler key
+type SchedulerKeyOptionFunc func(*schedulerKey)
+
 // String implement stringer
 func (sk schedulerKey) String() string {
 	s, _ := json.Marshal(sk)
@@ -25,20 +54,108 @@ func (sk schedulerKey) String() string {
 // * Custom start time and repeat duration, e.g:
 //   - 07:00@daily, will start at 07:00 UTC+7 and repeat every day
 //   - 07:00@weekly, will start at 07:00 UTC+7 and repeat every week
-//   - 07:00@monthly, will start at 07:00 UTC+7 and repeat every month
+//   - 07:00@monthly, will run at 07:00 UTC+7 on the 1st of every month
+//   - 07:00@yearly, will run at 07:00 UTC+7 on 1 January of every year
 //   - 07:00@10s, will start at 07:00 UTC+7 and next repeat every 10 seconds
 //   - 07:00@1m, will start at 07:00 UTC+7 and next repeat every 1 minute
-func CreateSchedulerKey(jobName, interval string) string {
-	return schedulerKey{JobName: jobName, Interval: interval}.String()
+//   - 07:00@businessday, will run at 07:00 UTC+7 from monday to friday except holidays (cronexpr.DefaultCalendar)
+//
+// the start time is in Asia/Jakarta unless the timezone is set with SchedulerKeyOptionTimezone
+//
+// the interval must be empty when the job is triggered by other jobs (SchedulerKeyOptionAfter, SchedulerKeyOptionOnFailure)
+func CreateSchedulerKey(jobName, interval string, opts ...SchedulerKeyOptionFunc) string {
+	sk := schedulerKey{JobName: jobName, Interval: interval}
+	for _, opt := range opts {
+		opt(&sk)
+	}
+
+	return sk.String()
 }
 
 // ParseSchedulerKey helpers
 func ParseSchedulerKey(val string) (string, string) {
+	sk := parseSchedulerKey(val)
+
+	return sk.JobName, sk.Interval
+}
+
+// parseSchedulerKey returns all values of the scheduler key
+func parseSchedulerKey(val string) schedulerKey {
 	var sk schedulerKey
 	err := json.Unmarshal([]byte(val), &sk)
 	if err != nil {
-		return "", ""
+		return schedulerKey{}
 	}
 
-	return sk.JobName, sk.Interval
+	return sk
+}
+
+// SchedulerKeyOptionTimezone set the location of the job schedule, e.g.: Asia/Makassar, Asia/Jayapura
+func SchedulerKeyOptionTimezone(tz string) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Timezone = tz
+	}
+}
+
+// SchedulerKeyOptionJitter delay every run with random duration between 0 and jitter
+func SchedulerKeyOptionJitter(jitter time.Duration) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Jitter = jitter
+	}
+}
+
+// SchedulerKeyOptionOverlapSkip skip the next run when the previous run still in progress
+func SchedulerKeyOptionOverlapSkip() SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Overlap = OverlapSkip
+		sk.MaxConcurrent = 1
+	}
+}
+
+// SchedulerKeyOptionOverlapQueue wait until the previous run is done before running the next one
+func SchedulerKeyOptionOverlapQueue() SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Overlap = OverlapQueue
+		sk.MaxConcurrent = 1
+	}
+}
+
+// SchedulerKeyOptionOverlapAllow allow up to maxConcurrent runs at the same time
+func SchedulerKeyOptionOverlapAllow(maxConcurrent int) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Overlap = OverlapAllow
+		sk.MaxConcurrent = maxConcurrent
+	}
+}
+
+// SchedulerKeyOptionMaxRuntime cancel the job context when the run exceeds maxRuntime
+func SchedulerKeyOptionMaxRuntime(maxRuntime time.Duration) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.MaxRuntime = maxRuntime
+	}
+}
+
+// SchedulerKeyOptionCalendar adjust the next run with cronexpr.DefaultCalendar when it's not a business day,
+// only for cron expression interval. e.g.: cronexpr.NextBusinessDay, cronexpr.PreviousBusinessDay, cronexpr.SkipHoliday
+func SchedulerKeyOptionCalendar(modifier cronexpr.Modifier) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.Modifier = modifier
+	}
+}
+
+// SchedulerKeyOptionAfter run the job when all the given jobs succeed (fan-in), e.g.:
+//
+//	cron.CreateSchedulerKey("settle", "", cron.SchedulerKeyOptionAfter("reconcile"))
+//	cron.CreateSchedulerKey("report", "", cron.SchedulerKeyOptionAfter("settle", "refund"))
+func SchedulerKeyOptionAfter(jobNames ...string) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.After = append(sk.After, jobNames...)
+	}
+}
+
+// SchedulerKeyOptionOnFailure run the job when one of the given jobs fails
+func SchedulerKeyOptionOnFailure(jobNames ...string) SchedulerKeyOptionFunc {
+	return func(sk *schedulerKey) {
+		sk.OnFailure = append(sk.OnFailure, jobNames...)
+	}
 }
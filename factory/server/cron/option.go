@@ -7,7 +7,8 @@ import (
 )
 
 type option struct {
-	debugMode     bool
+	debugMode bool
+	// maxGoroutines is the max concurrent runs of the job without overlap policy
 	maxGoroutines int
 	locker        cronexpr.Locker
 }
@@ -0,0 +1,128 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/mqdvi-dp/go-common/types"
+)
+
+// fields of the stream message
+const (
+	fieldPayload = "payload"
+	fieldKey     = "key"
+	fieldHeader  = "header"
+
+	// fields of the dead letter message
+	fieldStream        = "stream"
+	fieldMessageId     = "message_id"
+	fieldDeliveryCount = "delivery_count"
+)
+
+// Client publish the messages into redis streams, used by the publisher and the worker.
+// the stream is the topic, and the messages exceed the max delivery are moved into {stream}{dlq suffix}
+type Client struct {
+	rdc       rdc.Rdc
+	maxLen    int64
+	dlqSuffix string
+}
+
+// ClientOptionFunc option func for client
+type ClientOptionFunc func(*Client)
+
+// SetClientMaxLen set the approximate maximum length of the streams (MAXLEN ~), less than 1 is not trimmed
+func SetClientMaxLen(maxLen int64) ClientOptionFunc {
+	return func(c *Client) {
+		c.maxLen = maxLen
+	}
+}
+
+// SetClientDlqSuffix set the suffix of dead letter stream, must be the same for the publisher and the worker
+func SetClientDlqSuffix(dlqSuffix string) ClientOptionFunc {
+	return func(c *Client) {
+		c.dlqSuffix = dlqSuffix
+	}
+}
+
+// NewClient create new redis stream client
+func NewClient(client rdc.Rdc, opts ...ClientOptionFunc) *Client {
+	c := &Client{
+		rdc:       client,
+		maxLen:    env.GetInt64("REDIS_STREAM_MAX_LEN", 100000),
+		dlqSuffix: env.GetString("REDIS_STREAM_DLQ_SUFFIX", ":dlq"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) dlqStream(stream string) string {
+	return stream + c.dlqSuffix
+}
+
+// Publish append the message into the stream, returns the message id
+func (c *Client) Publish(ctx context.Context, stream string, payload []byte, key string, header map[string]interface{}) (id string, err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("RedisStream:Publish:%s", stream))
+	defer trace.Finish()
+
+	trace.SetTag("stream", stream)
+	trace.SetTag("key", key)
+	trace.Log("header", header)
+
+	if stream == "" {
+		err = fmt.Errorf("stream cannot be empty")
+		trace.SetError(err)
+		return
+	}
+
+	values := map[string]interface{}{fieldPayload: payload}
+	if key != "" {
+		values[fieldKey] = key
+	}
+	if len(header) > 0 {
+		h, err := json.Marshal(header)
+		if err != nil {
+			trace.SetError(err)
+			return "", err
+		}
+		values[fieldHeader] = string(h)
+	}
+
+	id, err = c.rdc.XAdd(ctx, stream, values, c.maxLen)
+	if err != nil {
+		trace.SetError(err)
+		return
+	}
+
+	trace.SetTag("message_id", id)
+	return id, nil
+}
+
+// PublishMessage append the message from publisher argument, topic is the stream
+func (c *Client) PublishMessage(ctx context.Context, arg *types.PublisherArgument) error {
+	if reflect.ValueOf(arg).IsZero() {
+		return fmt.Errorf("arguments cannot be empty")
+	}
+
+	_, err := c.Publish(ctx, arg.Topic, arg.Message, arg.Key, arg.Header)
+	return err
+}
+
+// PublishMessages append multiple messages, stop at the first error
+func (c *Client) PublishMessages(ctx context.Context, args []*types.PublisherArgument) error {
+	for _, arg := range args {
+		if err := c.PublishMessage(ctx, arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
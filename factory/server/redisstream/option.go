@@ -0,0 +1,93 @@
+package redisstream
+
+import (
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+)
+
+type option struct {
+	serviceName   string
+	consumerGroup string
+	consumer      string
+	startId       string
+	maxGoroutines int
+	batchSize     int64
+	block         time.Duration
+	claimInterval time.Duration
+	claimMinIdle  time.Duration
+}
+
+// OptionFunc option func for redis stream worker
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		consumerGroup: env.GetString("REDIS_STREAM_CONSUMER_GROUP"),
+		consumer:      env.GetString("REDIS_STREAM_CONSUMER"),
+		startId:       "0",
+		maxGoroutines: env.GetInt("BROKER_MAX_GOROUTINES", 20),
+		batchSize:     10,
+		block:         env.GetDuration("REDIS_STREAM_BLOCK", 5*time.Second),
+		claimInterval: env.GetDuration("REDIS_STREAM_CLAIM_INTERVAL", 30*time.Second),
+		claimMinIdle:  env.GetDuration("REDIS_STREAM_CLAIM_MIN_IDLE", 5*time.Minute),
+	}
+}
+
+// SetConsumerGroup set the consumer group, default is the service name
+func SetConsumerGroup(consumerGroup string) OptionFunc {
+	return func(o *option) {
+		o.consumerGroup = consumerGroup
+	}
+}
+
+// SetConsumer set the unique consumer name of the instance, default is the hostname
+func SetConsumer(consumer string) OptionFunc {
+	return func(o *option) {
+		o.consumer = consumer
+	}
+}
+
+// SetStartId set the first message id when the consumer group is created,
+// "0" consume the messages from the first message and "$" only the new messages
+func SetStartId(startId string) OptionFunc {
+	return func(o *option) {
+		o.startId = startId
+	}
+}
+
+// SetMaxGoroutines set maximum of concurrent messages
+func SetMaxGoroutines(maxGoroutines int) OptionFunc {
+	return func(o *option) {
+		o.maxGoroutines = maxGoroutines
+	}
+}
+
+// SetBatchSize set maximum of messages read on every XREADGROUP and XAUTOCLAIM
+func SetBatchSize(batchSize int64) OptionFunc {
+	return func(o *option) {
+		o.batchSize = batchSize
+	}
+}
+
+// SetBlock set how long XREADGROUP block when there is no new message
+func SetBlock(block time.Duration) OptionFunc {
+	return func(o *option) {
+		o.block = block
+	}
+}
+
+// SetClaimInterval set interval of reclaiming the pending messages
+func SetClaimInterval(claimInterval time.Duration) OptionFunc {
+	return func(o *option) {
+		o.claimInterval = claimInterval
+	}
+}
+
+// SetClaimMinIdle set how long the message is not acknowledged before reclaimed by the other consumers,
+// it is the maximum duration of a message run and the delay of retry on failure
+func SetClaimMinIdle(claimMinIdle time.Duration) OptionFunc {
+	return func(o *option) {
+		o.claimMinIdle = claimMinIdle
+	}
+}
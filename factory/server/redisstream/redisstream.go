@@ -0,0 +1,396 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/constants"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/factory"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/mqdvi-dp/go-common/types"
+	"github.com/redis/go-redis/v9"
+)
+
+type redisStreamWorker struct {
+	ctx        context.Context
+	cancelFunc func()
+	readCtx    context.Context
+	cancelRead func()
+	opt        option
+	client     *Client
+	semaphore  chan struct{}
+	wg         sync.WaitGroup
+	loops      sync.WaitGroup
+	handlers   map[string]types.WorkerHandler
+}
+
+// NewWorker create new redis streams worker with consumer group,
+// the stream is set with types.WorkerHandlerOptionTopic and the message is delivered at most types.WorkerHandler MaxRetry + 1 times.
+// the failed message is not acknowledged and retried after the claim min idle, then moved into the dead letter stream
+func NewWorker(service factory.ServiceFactory, opts ...OptionFunc) factory.AppServerFactory {
+	if service.GetDependencies().GetBroker(constants.RedisStream) == nil {
+		logger.Log.Fatalf("missing dependencies redis stream")
+	}
+
+	worker := &redisStreamWorker{
+		opt:      getDefaultOption(),
+		handlers: make(map[string]types.WorkerHandler),
+	}
+	for _, opt := range opts {
+		opt(&worker.opt)
+	}
+
+	if reflect.ValueOf(worker.opt.serviceName).IsZero() {
+		worker.opt.serviceName = service.Name()
+	}
+	if worker.opt.consumerGroup == "" {
+		worker.opt.consumerGroup = worker.opt.serviceName
+	}
+	if worker.opt.consumer == "" {
+		// the hostname is unique for every pod
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = uuid.NewString()
+		}
+		worker.opt.consumer = hostname
+	}
+
+	worker.ctx, worker.cancelFunc = context.WithCancel(context.Background())
+	worker.readCtx, worker.cancelRead = context.WithCancel(worker.ctx)
+	worker.client = service.GetDependencies().GetBroker(constants.RedisStream).GetConfiguration().(*Client)
+	worker.semaphore = make(chan struct{}, worker.opt.maxGoroutines)
+
+	if h := service.WorkerHandler(constants.RedisStream); h != nil {
+		var hg types.WorkerHandlerGroup
+		h.Register(&hg)
+
+		for _, handler := range hg.Handlers {
+			if handler.Topic == "" {
+				logger.Log.Fatal("stream not yet set. please set the stream using, types.WorkerHandlerOptionTopic(stream)")
+			}
+
+			worker.handlers[handler.Topic] = handler
+			logger.Yellow(fmt.Sprintf(`⇨ [REDIS-STREAM-WORKER] (stream): %-15s (group): %s (max retry): %d`, `"`+handler.Topic+`"`, worker.opt.consumerGroup, handler.MaxRetry))
+		}
+	}
+	logger.YellowBold(fmt.Sprintf("⇨ Redis stream worker running with %d stream", len(worker.handlers)))
+
+	return worker
+}
+
+func (r *redisStreamWorker) Name() string {
+	return constants.RedisStream.String()
+}
+
+func (r *redisStreamWorker) Serve() {
+	for stream := range r.handlers {
+		if err := r.client.rdc.XGroupCreate(r.ctx, stream, r.opt.consumerGroup, r.opt.startId); err != nil {
+			// the group is created again when reading the stream
+			logger.Red(fmt.Sprintf("redis_stream > failed to create group %s of %s: %s", r.opt.consumerGroup, stream, err))
+		}
+
+		r.loops.Add(2)
+		go r.consume(stream)
+		go r.reclaim(stream)
+	}
+
+	r.loops.Wait()
+}
+
+func (r *redisStreamWorker) Shutdown(_ context.Context) {
+	defer logger.RedBold("Stopping Redis Stream Worker")
+
+	// stop reading, no more message will be read or claimed
+	r.cancelRead()
+	r.loops.Wait()
+
+	if runningMessage := len(r.semaphore); runningMessage != 0 {
+		fmt.Printf("\x1b[34;1mRedis Stream Worker:\x1b[0m waiting %d message until done...\n", runningMessage)
+	}
+
+	r.wg.Wait()
+	r.cancelFunc()
+}
+
+// acquire wait a free goroutine, then take up to n free goroutines, returns the number of taken goroutines
+func (r *redisStreamWorker) acquire(n int64) int64 {
+	select {
+	case <-r.readCtx.Done():
+		return 0
+	case r.semaphore <- struct{}{}:
+	}
+
+	acquired := int64(1)
+	for ; acquired < n; acquired++ {
+		select {
+		case r.semaphore <- struct{}{}:
+		default:
+			return acquired
+		}
+	}
+
+	return acquired
+}
+
+func (r *redisStreamWorker) release(n int64) {
+	for ; n > 0; n-- {
+		<-r.semaphore
+	}
+}
+
+// consume read the new messages of the stream until shutdown
+func (r *redisStreamWorker) consume(stream string) {
+	defer r.loops.Done()
+
+	for r.readCtx.Err() == nil {
+		count := r.acquire(r.opt.batchSize)
+		if count < 1 {
+			return
+		}
+
+		messages, err := r.client.rdc.XReadGroup(r.readCtx, r.opt.consumerGroup, r.opt.consumer, stream, count, r.opt.block)
+		r.release(count - int64(len(messages)))
+		if err != nil {
+			if errors.Is(err, redis.Nil) || r.readCtx.Err() != nil {
+				continue
+			}
+
+			logger.Red(fmt.Sprintf("redis_stream > failed to read %s: %s", stream, err))
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				_ = r.client.rdc.XGroupCreate(r.ctx, stream, r.opt.consumerGroup, r.opt.startId)
+			}
+
+			select {
+			case <-r.readCtx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			r.wg.Add(1)
+			go func(message redis.XMessage) {
+				defer func() {
+					r.wg.Done()
+					<-r.semaphore
+				}()
+
+				r.processMessage(stream, message, 1)
+			}(message)
+		}
+	}
+}
+
+// reclaim claim the messages which are not acknowledged longer than the claim min idle,
+// the messages of the crashed consumers and the failed messages are processed again
+func (r *redisStreamWorker) reclaim(stream string) {
+	defer r.loops.Done()
+
+	ticker := time.NewTicker(r.opt.claimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.readCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := "0-0"
+		for r.readCtx.Err() == nil {
+			messages, next, err := r.client.rdc.XAutoClaim(r.readCtx, stream, r.opt.consumerGroup, r.opt.consumer, r.opt.claimMinIdle, start, r.opt.batchSize)
+			if err != nil {
+				if r.readCtx.Err() == nil {
+					logger.Red(fmt.Sprintf("redis_stream > failed to claim pending messages of %s: %s", stream, err))
+				}
+				break
+			}
+
+			for _, message := range messages {
+				deliveries := r.deliveries(stream, message.ID)
+				if deliveries > int64(r.handlers[stream].MaxRetry)+1 {
+					r.deadLetter(stream, message, deliveries)
+					continue
+				}
+
+				if r.acquire(1) < 1 {
+					return
+				}
+
+				r.wg.Add(1)
+				go func(message redis.XMessage) {
+					defer func() {
+						r.wg.Done()
+						<-r.semaphore
+					}()
+
+					r.processMessage(stream, message, deliveries)
+				}(message)
+			}
+
+			// all pending messages are scanned
+			if next == "" || next == "0-0" {
+				break
+			}
+			start = next
+		}
+	}
+}
+
+// deliveries returns how many times the message is delivered, including the current delivery
+func (r *redisStreamWorker) deliveries(stream, id string) int64 {
+	pending, err := r.client.rdc.XPending(r.ctx, stream, r.opt.consumerGroup, id, id, 1)
+	if err != nil || len(pending) < 1 {
+		return 1
+	}
+
+	return pending[0].RetryCount
+}
+
+// deadLetter move the message into the dead letter stream and acknowledge the message
+func (r *redisStreamWorker) deadLetter(stream string, message redis.XMessage, deliveries int64) {
+	values := make(map[string]interface{}, len(message.Values)+3)
+	for key, val := range message.Values {
+		values[key] = val
+	}
+	values[fieldStream] = stream
+	values[fieldMessageId] = message.ID
+	values[fieldDeliveryCount] = deliveries
+
+	logger.Red(fmt.Sprintf("redis_stream > message %s of %s exceed max delivery, moved into %s", message.ID, stream, r.client.dlqStream(stream)))
+	if _, err := r.client.rdc.XAdd(r.ctx, r.client.dlqStream(stream), values, r.client.maxLen); err != nil {
+		// keep the message pending, it will be moved on the next claim
+		logger.Red(fmt.Sprintf("redis_stream > failed to move message %s of %s into dead letter: %s", message.ID, stream, err))
+		return
+	}
+
+	_, _ = r.client.rdc.XAck(r.ctx, stream, r.opt.consumerGroup, message.ID)
+}
+
+func (r *redisStreamWorker) processMessage(stream string, message redis.XMessage, deliveries int64) {
+	start := time.Now()
+	handler := r.handlers[stream]
+
+	if r.ctx.Err() != nil {
+		logger.Red(fmt.Sprintf("redis_stream > ctx root err: %s", r.ctx.Err()))
+		return
+	}
+
+	// the handler must finish before the message is claimed by the other consumers
+	ctx, cancel := context.WithTimeout(r.ctx, r.opt.claimMinIdle)
+	defer cancel()
+
+	header := map[string]interface{}{
+		"message_id":     message.ID,
+		"stream":         stream,
+		"consumer_group": r.opt.consumerGroup,
+		"attempt":        deliveries,
+	}
+	if h, ok := message.Values[fieldHeader].(string); ok {
+		var published map[string]interface{}
+		if err := json.Unmarshal([]byte(h), &published); err == nil {
+			for key, val := range published {
+				header[key] = val
+			}
+		}
+	}
+
+	payload, key := r.payload(message)
+	reqBody := payload
+	if len(reqBody) > env.GetInt("MAX_BODY_SIZE", 1500) {
+		reqBody = []byte(fmt.Sprintf("request body too long %d", len(reqBody)))
+	}
+
+	// init logger data
+	ol := &logger.Logger{
+		StartTime:     start.Format(time.RFC3339),
+		RequestId:     uuid.NewString(),
+		HandlerType:   logger.RedisStream,
+		Service:       r.opt.serviceName,
+		Endpoint:      fmt.Sprintf("stream: %s", stream),
+		RequestBody:   string(reqBody),
+		RequestHeader: fmt.Sprintf("Stream: %s | Header: %v", stream, header),
+	}
+
+	var err error
+	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("RedisStream:%s", stream))
+	defer func() {
+		if re := recover(); re != nil {
+			err = fmt.Errorf("%s", re)
+		}
+
+		sc := http.StatusOK
+		if err != nil {
+			trace.SetError(err)
+			sc = http.StatusInternalServerError
+			ol.ErrorMessage = fmt.Sprintf("%s", err)
+			// not acknowledged, the message is claimed again after the claim min idle
+			logger.Red(fmt.Sprintf("redis_stream > message %s of %s failed (attempt: %d): %s", message.ID, stream, deliveries, err))
+		} else {
+			ol.ResponseBody = "success"
+			// use root context, the message context may be already timeout
+			if _, e := r.client.rdc.XAck(r.ctx, stream, r.opt.consumerGroup, message.ID); e != nil {
+				logger.Red(fmt.Sprintf("redis_stream > failed to ack message %s of %s: %s", message.ID, stream, e))
+			}
+		}
+
+		since := time.Since(start)
+		ol.StatusCode = sc
+		ol.ExecutionTime = since.Seconds()
+
+		trace.SetTag("trace_id", tracer.GetTraceId(ctx))
+		trace.Finish()
+		monitoring.RecordPrometheus(sc, constants.RedisStream.String(), ol.Endpoint, since)
+		// when disable trace is false
+		// means we will trace the logs
+		if !handler.DisableTrace || err != nil {
+			ol.Finalize(ctx)
+		}
+	}()
+
+	trace.SetTag("stream", stream)
+	trace.SetTag("message_id", message.ID)
+	trace.SetTag("consumer_group", r.opt.consumerGroup)
+	trace.Log("header", header)
+	trace.Log("payload", payload)
+
+	var lock = logger.NewLocker(ctx)
+	// set to context with logger.LogKey as a context key
+	ctx = context.WithValue(ctx, logger.LogKey, lock)
+
+	var ec types.EventContext
+	ec.SetContext(ctx)
+	ec.SetWorkerType(constants.RedisStream.String())
+	ec.SetTopic(stream)
+	ec.SetHeader(header)
+	ec.SetKey(key)
+	_, _ = ec.Write(payload)
+
+	if err = handler.HandlerFunc(&ec); err != nil {
+		ec.SetError(err)
+	}
+}
+
+// payload returns the payload and the key of the message,
+// the message not published by Client (without payload field) is returned as json of the fields
+func (r *redisStreamWorker) payload(message redis.XMessage) ([]byte, string) {
+	key, _ := message.Values[fieldKey].(string)
+	if payload, ok := message.Values[fieldPayload].(string); ok {
+		return []byte(payload), key
+	}
+
+	payload, _ := json.Marshal(message.Values)
+	return payload, key
+}
@@ -0,0 +1,286 @@
+package redistask
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/mqdvi-dp/go-common/types"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// HeaderDelay header key of types.PublisherArgument to delay the task, value is time.Duration or duration string
+	HeaderDelay = "delay"
+	// HeaderProcessAt header key of types.PublisherArgument to process the task at, value is time.Time or RFC3339 string
+	HeaderProcessAt = "process_at"
+)
+
+// Client store the delayed tasks in redis sorted sets, used by the publisher and the worker
+//
+// keys, the task name is the hashtag so the sorted sets of the task are in the same slot:
+//   - {prefix}:{task name}:scheduled, sorted set of task id with score the process time
+//   - {prefix}:{task name}:processing, sorted set of task id with score the visibility deadline
+//   - {prefix}:{task name}:dead, sorted set of task id which exceed the max retry
+//   - {prefix}:task:{task id}, the task data
+//   - {prefix}:unique:{task name}:{unique key}, the uniqueness of the task
+type Client struct {
+	rdc       rdc.Rdc
+	prefix    string
+	retention time.Duration
+}
+
+// ClientOptionFunc option func for client
+type ClientOptionFunc func(*Client)
+
+// SetClientPrefix set prefix of redis keys, must be the same for the publisher and the worker
+func SetClientPrefix(prefix string) ClientOptionFunc {
+	return func(c *Client) {
+		c.prefix = prefix
+	}
+}
+
+// SetClientRetention set how long the task data kept after the process time
+func SetClientRetention(retention time.Duration) ClientOptionFunc {
+	return func(c *Client) {
+		c.retention = retention
+	}
+}
+
+// NewClient create new redis task client
+func NewClient(client rdc.Rdc, opts ...ClientOptionFunc) *Client {
+	c := &Client{
+		rdc:       client,
+		prefix:    env.GetString("REDIS_TASK_PREFIX", "redis-task"),
+		retention: env.GetDuration("REDIS_TASK_RETENTION", 7*24*time.Hour),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) scheduledKey(name string) string {
+	return fmt.Sprintf("%s:{%s}:scheduled", c.prefix, name)
+}
+
+func (c *Client) processingKey(name string) string {
+	return fmt.Sprintf("%s:{%s}:processing", c.prefix, name)
+}
+
+func (c *Client) deadKey(name string) string {
+	return fmt.Sprintf("%s:{%s}:dead", c.prefix, name)
+}
+
+func (c *Client) taskKey(id string) string {
+	return fmt.Sprintf("%s:task:%s", c.prefix, id)
+}
+
+func (c *Client) uniqueKey(t *task) string {
+	return fmt.Sprintf("%s:unique:%s:%s", c.prefix, t.Name, t.UniqueKey)
+}
+
+// Enqueue add the task into queue, returns the task id.
+// the task is processed immediately unless TaskOptionDelay or TaskOptionProcessAt is set
+func (c *Client) Enqueue(ctx context.Context, name string, payload []byte, opts ...TaskOptionFunc) (id string, err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("RedisTask:Enqueue:%s", name))
+	defer trace.Finish()
+
+	now := time.Now()
+	t := &task{
+		Id:        uuid.NewString(),
+		Name:      name,
+		Payload:   payload,
+		ProcessAt: now,
+		CreatedAt: now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	trace.SetTag("task_name", t.Name)
+	trace.SetTag("task_id", t.Id)
+	trace.Log("process_at", t.ProcessAt)
+
+	if name == "" {
+		err = fmt.Errorf("task name cannot be empty")
+		trace.SetError(err)
+		return
+	}
+
+	if t.UniqueKey != "" {
+		ttl := t.UniqueTTL
+		if ttl <= 0 {
+			ttl = c.ttl(t)
+		}
+
+		var ok bool
+		ok, err = c.rdc.SetNX(ctx, c.uniqueKey(t), t.Id, ttl)
+		if err != nil {
+			trace.SetError(err)
+			return
+		}
+
+		if !ok {
+			err = ErrDuplicateTask
+			trace.SetError(err)
+			return
+		}
+	}
+
+	if err = c.save(ctx, t); err != nil {
+		trace.SetError(err)
+		c.release(ctx, t)
+		return
+	}
+
+	if err = c.rdc.ZAdd(ctx, c.scheduledKey(t.Name), score(t.ProcessAt), t.Id); err != nil {
+		trace.SetError(err)
+		_ = c.rdc.Del(ctx, c.taskKey(t.Id))
+		c.release(ctx, t)
+		return
+	}
+
+	return t.Id, nil
+}
+
+// Cancel remove the task which is not yet processed
+func (c *Client) Cancel(ctx context.Context, id string) (err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "RedisTask:Cancel")
+	defer trace.Finish()
+
+	trace.SetTag("task_id", id)
+
+	t, err := c.load(ctx, id)
+	if err != nil {
+		trace.SetError(err)
+		return
+	}
+
+	removed, err := c.rdc.ZRem(ctx, c.scheduledKey(t.Name), t.Id)
+	if err != nil {
+		trace.SetError(err)
+		return
+	}
+
+	if removed == 0 {
+		err = ErrTaskRunning
+		trace.SetError(err)
+		return
+	}
+
+	c.release(ctx, t)
+	return c.rdc.Del(ctx, c.taskKey(t.Id))
+}
+
+// PublishMessage enqueue the task from publisher argument,
+// topic is the task name, key is the unique key and the delay is set by HeaderDelay or HeaderProcessAt
+func (c *Client) PublishMessage(ctx context.Context, arg *types.PublisherArgument) error {
+	if reflect.ValueOf(arg).IsZero() {
+		return fmt.Errorf("arguments cannot be empty")
+	}
+
+	opts := []TaskOptionFunc{TaskOptionHeader(arg.Header)}
+	if arg.Key != "" {
+		opts = append(opts, TaskOptionUniqueKey(arg.Key, 0))
+	}
+
+	switch delay := arg.Header[HeaderDelay].(type) {
+	case time.Duration:
+		opts = append(opts, TaskOptionDelay(delay))
+	case string:
+		d, err := time.ParseDuration(delay)
+		if err != nil {
+			return fmt.Errorf("invalid header %s: %s", HeaderDelay, err)
+		}
+		opts = append(opts, TaskOptionDelay(d))
+	}
+
+	switch processAt := arg.Header[HeaderProcessAt].(type) {
+	case time.Time:
+		opts = append(opts, TaskOptionProcessAt(processAt))
+	case string:
+		t, err := time.Parse(time.RFC3339, processAt)
+		if err != nil {
+			return fmt.Errorf("invalid header %s: %s", HeaderProcessAt, err)
+		}
+		opts = append(opts, TaskOptionProcessAt(t))
+	}
+
+	_, err := c.Enqueue(ctx, arg.Topic, arg.Message, opts...)
+	return err
+}
+
+// PublishMessages enqueue multiple tasks, stop at the first error
+func (c *Client) PublishMessages(ctx context.Context, args []*types.PublisherArgument) error {
+	for _, arg := range args {
+		if err := c.PublishMessage(ctx, arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ttl of the task data, until the retention after the process time
+func (c *Client) ttl(t *task) time.Duration {
+	ttl := time.Until(t.ProcessAt) + c.retention
+	if ttl < c.retention {
+		ttl = c.retention
+	}
+
+	return ttl
+}
+
+// save the task data
+func (c *Client) save(ctx context.Context, t *task) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	return c.rdc.Set(ctx, c.taskKey(t.Id), string(b), rdc.WithTTL(c.ttl(t)))
+}
+
+// load the task data
+func (c *Client) load(ctx context.Context, id string) (*task, error) {
+	var t task
+	if err := c.rdc.GetStruct(ctx, &t, c.taskKey(id)); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// move the task id between the sorted sets atomically, due is the maximum score of the task
+// in the source (empty is not checked). returns false when the task is not moved
+func (c *Client) move(ctx context.Context, from, to, id, due string, at time.Time) (bool, error) {
+	result, err := c.rdc.EvalScript(ctx, move, []string{from, to}, id, due, scoreString(at))
+	if err != nil {
+		return false, err
+	}
+
+	n, _ := result.(int64)
+	return n == 1, nil
+}
+
+// release the unique key of the task
+func (c *Client) release(ctx context.Context, t *task) {
+	if t.UniqueKey == "" {
+		return
+	}
+
+	_ = c.rdc.Del(ctx, c.uniqueKey(t))
+}
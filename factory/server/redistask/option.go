@@ -0,0 +1,65 @@
+package redistask
+
+import (
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+)
+
+type option struct {
+	serviceName       string
+	maxGoroutines     int
+	pollInterval      time.Duration
+	visibilityTimeout time.Duration
+	batchSize         int64
+	maxBackoff        time.Duration
+}
+
+// OptionFunc option func for redis task worker
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		maxGoroutines:     env.GetInt("BROKER_MAX_GOROUTINES", 20),
+		pollInterval:      env.GetDuration("REDIS_TASK_POLL_INTERVAL", time.Second),
+		visibilityTimeout: env.GetDuration("REDIS_TASK_VISIBILITY_TIMEOUT", 5*time.Minute),
+		batchSize:         100,
+		maxBackoff:        env.GetDuration("REDIS_TASK_MAX_BACKOFF", time.Hour),
+	}
+}
+
+// SetMaxGoroutines set maximum of concurrent tasks
+func SetMaxGoroutines(maxGoroutines int) OptionFunc {
+	return func(o *option) {
+		o.maxGoroutines = maxGoroutines
+	}
+}
+
+// SetPollInterval set interval of polling the due tasks
+func SetPollInterval(pollInterval time.Duration) OptionFunc {
+	return func(o *option) {
+		o.pollInterval = pollInterval
+	}
+}
+
+// SetVisibilityTimeout set maximum duration of a task run,
+// the task is delivered again when the worker does not finish the task before the timeout (e.g.: the pod is killed)
+func SetVisibilityTimeout(visibilityTimeout time.Duration) OptionFunc {
+	return func(o *option) {
+		o.visibilityTimeout = visibilityTimeout
+	}
+}
+
+// SetBatchSize set maximum of due tasks fetched on every poll
+func SetBatchSize(batchSize int64) OptionFunc {
+	return func(o *option) {
+		o.batchSize = batchSize
+	}
+}
+
+// SetMaxBackoff set maximum delay between retries
+func SetMaxBackoff(maxBackoff time.Duration) OptionFunc {
+	return func(o *option) {
+		o.maxBackoff = maxBackoff
+	}
+}
@@ -0,0 +1,360 @@
+package redistask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/constants"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/factory"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/monitoring"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/mqdvi-dp/go-common/types"
+)
+
+type redisTaskWorker struct {
+	ctx        context.Context
+	cancelFunc func()
+	opt        option
+	client     *Client
+	shutdown   chan struct{}
+	closeOnce  sync.Once
+	semaphore  chan struct{}
+	wg         sync.WaitGroup
+	polling    sync.Mutex
+	handlers   map[string]types.WorkerHandler
+}
+
+// NewWorker create new redis delayed task worker,
+// the task name is set with types.WorkerHandlerOptionTopic and retried with types.WorkerHandler MaxRetry and RetryBackoff
+func NewWorker(service factory.ServiceFactory, opts ...OptionFunc) factory.AppServerFactory {
+	if service.GetDependencies().GetBroker(constants.RedisTask) == nil {
+		logger.Log.Fatalf("missing dependencies redis task")
+	}
+
+	worker := &redisTaskWorker{
+		opt:      getDefaultOption(),
+		shutdown: make(chan struct{}),
+		handlers: make(map[string]types.WorkerHandler),
+	}
+	for _, opt := range opts {
+		opt(&worker.opt)
+	}
+
+	if reflect.ValueOf(worker.opt.serviceName).IsZero() {
+		worker.opt.serviceName = service.Name()
+	}
+
+	worker.ctx, worker.cancelFunc = context.WithCancel(context.Background())
+	worker.client = service.GetDependencies().GetBroker(constants.RedisTask).GetConfiguration().(*Client)
+	worker.semaphore = make(chan struct{}, worker.opt.maxGoroutines)
+
+	if h := service.WorkerHandler(constants.RedisTask); h != nil {
+		var hg types.WorkerHandlerGroup
+		h.Register(&hg)
+
+		for _, handler := range hg.Handlers {
+			if handler.Topic == "" {
+				logger.Log.Fatal("task name not yet set. please set the task name using, types.WorkerHandlerOptionTopic(name)")
+			}
+
+			worker.handlers[handler.Topic] = handler
+			logger.Yellow(fmt.Sprintf(`⇨ [REDIS-TASK-WORKER] (task): %-15s (max retry): %d`, `"`+handler.Topic+`"`, handler.MaxRetry))
+		}
+	}
+	logger.YellowBold(fmt.Sprintf("⇨ Redis task worker running with %d task", len(worker.handlers)))
+
+	return worker
+}
+
+func (r *redisTaskWorker) Name() string {
+	return constants.RedisTask.String()
+}
+
+func (r *redisTaskWorker) Serve() {
+	if len(r.handlers) < 1 {
+		return
+	}
+
+	ticker := time.NewTicker(r.opt.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.shutdown:
+			return
+		case <-ticker.C:
+		}
+
+		r.pollAll()
+	}
+}
+
+// pollAll poll every registered task, skipped when the worker is shutting down
+func (r *redisTaskWorker) pollAll() {
+	r.polling.Lock()
+	defer r.polling.Unlock()
+
+	for name := range r.handlers {
+		select {
+		case <-r.shutdown:
+			return
+		default:
+		}
+
+		r.requeueExpired(name)
+		r.poll(name)
+	}
+}
+
+func (r *redisTaskWorker) Shutdown(_ context.Context) {
+	defer logger.RedBold("Stopping Redis Task Worker")
+
+	// shutdown can be called more than once
+	r.closeOnce.Do(func() { close(r.shutdown) })
+	// wait the current poll, no more task will be claimed
+	r.polling.Lock()
+	defer r.polling.Unlock()
+
+	if runningTask := len(r.semaphore); runningTask != 0 {
+		fmt.Printf("\x1b[34;1mRedis Task Worker:\x1b[0m waiting %d task until done...\n", runningTask)
+	}
+
+	r.wg.Wait()
+	r.cancelFunc()
+}
+
+// requeueExpired retry the tasks which exceed the visibility timeout as failed attempt,
+// so the task which keeps crashing or hanging the worker is moved into dead tasks after the max retry
+func (r *redisTaskWorker) requeueExpired(name string) {
+	now := time.Now()
+	ids, err := r.client.rdc.ZRangeByScore(r.ctx, r.client.processingKey(name), "-inf", scoreString(now), 0, r.opt.batchSize)
+	if err != nil {
+		logger.Red(fmt.Sprintf("redis_task > failed to get expired task %s: %s", name, err))
+		return
+	}
+
+	for _, id := range ids {
+		// claim the expired task again, so only one worker counts the attempt and the other workers skip it
+		processingKey := r.client.processingKey(name)
+		claimed, err := r.client.move(r.ctx, processingKey, processingKey, id, scoreString(now), now.Add(r.opt.visibilityTimeout))
+		if err != nil {
+			logger.Red(fmt.Sprintf("redis_task > failed to requeue task %s (id: %s): %s", name, id, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		t, err := r.client.load(r.ctx, id)
+		if err != nil {
+			if !errors.Is(err, ErrTaskNotFound) {
+				// keep the task in processing, it will be requeued after the visibility timeout
+				logger.Red(fmt.Sprintf("redis_task > failed to load expired task %s (id: %s): %s", name, id, err))
+				continue
+			}
+
+			// the task is cancelled or expired
+			_, _ = r.client.rdc.ZRem(r.ctx, processingKey, id)
+			continue
+		}
+
+		logger.Yellow(fmt.Sprintf("redis_task > task %s (id: %s) exceed visibility timeout", name, id))
+		r.retry(r.handlers[name], t, errVisibilityTimeout)
+	}
+}
+
+// poll claim the due tasks and process them
+func (r *redisTaskWorker) poll(name string) {
+	now := time.Now()
+	ids, err := r.client.rdc.ZRangeByScore(r.ctx, r.client.scheduledKey(name), "-inf", scoreString(now), 0, r.opt.batchSize)
+	if err != nil {
+		logger.Red(fmt.Sprintf("redis_task > failed to get due task %s: %s", name, err))
+		return
+	}
+
+	for _, id := range ids {
+		select {
+		case r.semaphore <- struct{}{}:
+		default:
+			// all goroutines are busy, the rest of tasks processed on the next poll
+			return
+		}
+
+		// claim the task, only one worker can move the due task from the scheduled into the processing tasks
+		deadline := time.Now().Add(r.opt.visibilityTimeout)
+		claimed, err := r.client.move(r.ctx, r.client.scheduledKey(name), r.client.processingKey(name), id, scoreString(now), deadline)
+		if err != nil || !claimed {
+			if err != nil {
+				logger.Red(fmt.Sprintf("redis_task > failed to claim task %s (id: %s): %s", name, id, err))
+			}
+
+			<-r.semaphore
+			continue
+		}
+
+		r.wg.Add(1)
+		go func(id string) {
+			defer func() {
+				r.wg.Done()
+				<-r.semaphore
+			}()
+
+			r.processTask(name, id, deadline)
+		}(id)
+	}
+}
+
+// processTask run the handler of the claimed task, the deadline is the visibility deadline of the claim
+func (r *redisTaskWorker) processTask(name, id string, deadline time.Time) {
+	start := time.Now()
+	handler := r.handlers[name]
+
+	if r.ctx.Err() != nil {
+		logger.Red(fmt.Sprintf("redis_task > ctx root err: %s", r.ctx.Err()))
+		return
+	}
+
+	// the handler must finish before the task is delivered again, the deadline starts from the claim
+	ctx, cancel := context.WithDeadline(r.ctx, deadline)
+	defer cancel()
+
+	t, err := r.client.load(ctx, id)
+	if err != nil {
+		if !errors.Is(err, ErrTaskNotFound) {
+			// keep the task in processing, it will be requeued after the visibility timeout
+			logger.Red(fmt.Sprintf("redis_task > failed to load task %s (id: %s): %s", name, id, err))
+			return
+		}
+
+		// the task is cancelled or expired
+		_, _ = r.client.rdc.ZRem(ctx, r.client.processingKey(name), id)
+		return
+	}
+
+	header := map[string]interface{}{
+		"task_id":    t.Id,
+		"attempt":    t.Attempt + 1,
+		"process_at": t.ProcessAt.Format(time.RFC3339),
+	}
+	for key, val := range t.Header {
+		header[key] = val
+	}
+
+	reqBody := t.Payload
+	if len(reqBody) > env.GetInt("MAX_BODY_SIZE", 1500) {
+		reqBody = []byte(fmt.Sprintf("request body too long %d", len(reqBody)))
+	}
+
+	// init logger data
+	ol := &logger.Logger{
+		StartTime:     start.Format(time.RFC3339),
+		RequestId:     uuid.NewString(),
+		HandlerType:   logger.RedisTask,
+		Service:       r.opt.serviceName,
+		Endpoint:      fmt.Sprintf("task: %s", name),
+		RequestBody:   string(reqBody),
+		RequestHeader: fmt.Sprintf("Task: %s | Header: %v", name, header),
+	}
+
+	trace, ctx := tracer.StartTraceWithContext(ctx, fmt.Sprintf("RedisTask:%s", name))
+	defer func() {
+		if re := recover(); re != nil {
+			err = fmt.Errorf("%s", re)
+		}
+
+		sc := http.StatusOK
+		if err != nil {
+			trace.SetError(err)
+			sc = http.StatusInternalServerError
+			ol.ErrorMessage = fmt.Sprintf("%s", err)
+		} else {
+			ol.ResponseBody = "success"
+		}
+		r.complete(handler, t, err)
+
+		since := time.Since(start)
+		ol.StatusCode = sc
+		ol.ExecutionTime = since.Seconds()
+
+		trace.SetTag("trace_id", tracer.GetTraceId(ctx))
+		trace.Finish()
+
+		ol.Finalize(ctx)
+		monitoring.RecordPrometheus(sc, constants.RedisTask.String(), ol.Endpoint, since)
+	}()
+
+	trace.SetTag("task_id", t.Id)
+	trace.SetTag("task_name", name)
+	trace.Log("header", header)
+	trace.Log("payload", t.Payload)
+
+	var lock = logger.NewLocker(ctx)
+	// set to context with logger.LogKey as a context key
+	ctx = context.WithValue(ctx, logger.LogKey, lock)
+
+	var ec types.EventContext
+	ec.SetContext(ctx)
+	ec.SetWorkerType(constants.RedisTask.String())
+	ec.SetTopic(name)
+	ec.SetHeader(header)
+	ec.SetKey(t.Id)
+	_, _ = ec.Write(t.Payload)
+
+	if err = handler.HandlerFunc(&ec); err != nil {
+		ec.SetError(err)
+	}
+}
+
+// complete remove the task when succeed, otherwise schedule the retry with exponential backoff
+// or move the task into dead tasks when exceed the max retry
+func (r *redisTaskWorker) complete(handler types.WorkerHandler, t *task, err error) {
+	// use root context, the task context may be already timeout
+	ctx := r.ctx
+	processingKey := r.client.processingKey(t.Name)
+
+	if err == nil {
+		r.client.release(ctx, t)
+		_ = r.client.rdc.Del(ctx, r.client.taskKey(t.Id))
+		_, _ = r.client.rdc.ZRem(ctx, processingKey, t.Id)
+		return
+	}
+
+	r.retry(handler, t, err)
+}
+
+// retry schedule the retry of the processing task with exponential backoff or move the task into dead tasks
+// when exceed the max retry
+func (r *redisTaskWorker) retry(handler types.WorkerHandler, t *task, err error) {
+	ctx := r.ctx
+	processingKey := r.client.processingKey(t.Name)
+
+	t.Attempt++
+	t.LastError = err.Error()
+	if t.Attempt > handler.MaxRetry {
+		logger.Red(fmt.Sprintf("redis_task > task %s (id: %s) exceed max retry: %s", t.Name, t.Id, err))
+		r.client.release(ctx, t)
+		_ = r.client.save(ctx, t)
+		_, _ = r.client.move(ctx, processingKey, r.client.deadKey(t.Name), t.Id, "", time.Now())
+		return
+	}
+
+	t.ProcessAt = time.Now().Add(retryBackoff(handler.RetryBackoff, r.opt.maxBackoff, t.Attempt))
+	if err = r.client.save(ctx, t); err != nil {
+		// keep the task in processing, it will be requeued after the visibility timeout
+		logger.Red(fmt.Sprintf("redis_task > failed to retry task %s (id: %s): %s", t.Name, t.Id, err))
+		return
+	}
+
+	if _, err = r.client.move(ctx, processingKey, r.client.scheduledKey(t.Name), t.Id, "", t.ProcessAt); err != nil {
+		// keep the task in processing, it will be requeued after the visibility timeout
+		logger.Red(fmt.Sprintf("redis_task > failed to retry task %s (id: %s): %s", t.Name, t.Id, err))
+	}
+}
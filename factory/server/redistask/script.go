@@ -0,0 +1,21 @@
+package redistask
+
+import "github.com/mqdvi-dp/go-common/config/database/rdc"
+
+// move the task id from KEYS[1] into KEYS[2] with the score ARGV[3] atomically,
+// so the task is never lost between the sorted sets. when ARGV[2] is not empty, the task is moved only when
+// the score is due (less than or equal ARGV[2]). returns 1 when the task is moved, 0 when the task is not found
+// (claimed by another worker) or not yet due
+var move = rdc.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score then
+	return 0
+end
+if ARGV[2] ~= '' and tonumber(score) > tonumber(ARGV[2]) then
+	return 0
+end
+
+redis.call('ZREM', KEYS[1], ARGV[1])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[1])
+return 1
+`)
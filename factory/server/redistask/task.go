@@ -0,0 +1,100 @@
+package redistask
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrDuplicateTask the task with the same unique key already enqueued
+	ErrDuplicateTask = errors.New("task with the same unique key already exists")
+	// ErrTaskNotFound the task is not found, already done or expired
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskRunning the task is running and cannot be cancelled
+	ErrTaskRunning = errors.New("task is running")
+	// errVisibilityTimeout the task is not done before the visibility timeout, e.g.: the worker crashed
+	errVisibilityTimeout = errors.New("task exceed visibility timeout")
+)
+
+// task model stored in redis
+type task struct {
+	Id        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Payload   []byte                 `json:"payload"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	UniqueKey string                 `json:"uniqueKey,omitempty"`
+	UniqueTTL time.Duration          `json:"uniqueTtl,omitempty"`
+	Attempt   int                    `json:"attempt"`
+	LastError string                 `json:"lastError,omitempty"`
+	ProcessAt time.Time              `json:"processAt"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// TaskOptionFunc option func for enqueue task
+type TaskOptionFunc func(*task)
+
+// TaskOptionId set custom task id, default is uuid
+func TaskOptionId(id string) TaskOptionFunc {
+	return func(t *task) {
+		t.Id = id
+	}
+}
+
+// TaskOptionDelay process the task after delay, e.g.: expire the order in 15 minutes
+func TaskOptionDelay(delay time.Duration) TaskOptionFunc {
+	return func(t *task) {
+		t.ProcessAt = time.Now().Add(delay)
+	}
+}
+
+// TaskOptionProcessAt process the task at the given time
+func TaskOptionProcessAt(processAt time.Time) TaskOptionFunc {
+	return func(t *task) {
+		t.ProcessAt = processAt
+	}
+}
+
+// TaskOptionUniqueKey reject the task when another task with the same name and unique key still exists,
+// the uniqueness is released when the task is done, cancelled or after ttl (if greater than 0)
+func TaskOptionUniqueKey(uniqueKey string, ttl time.Duration) TaskOptionFunc {
+	return func(t *task) {
+		t.UniqueKey = uniqueKey
+		t.UniqueTTL = ttl
+	}
+}
+
+// TaskOptionHeader set header of the task, the header is passed into types.EventContext
+func TaskOptionHeader(header map[string]interface{}) TaskOptionFunc {
+	return func(t *task) {
+		t.Header = header
+	}
+}
+
+// retryBackoff returns the exponential backoff (base * 2^(attempt-1)) of the attempt, capped by the max backoff.
+// the shift is checked before, so the backoff does not overflow on the high attempts
+func retryBackoff(base, maxBackoff time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	shift := uint(attempt - 1)
+	if shift >= 63 || base > maxBackoff>>shift {
+		return maxBackoff
+	}
+
+	return base << shift
+}
+
+// score of the sorted set in millisecond
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// scoreString of the sorted set for range query
+func scoreString(t time.Time) string {
+	return fmt.Sprintf("%d", t.UnixMilli())
+}
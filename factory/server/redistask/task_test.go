@@ -0,0 +1,33 @@
+package redistask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       time.Duration
+		maxBackoff time.Duration
+		attempt    int
+		want       time.Duration
+	}{
+		{name: "zero attempt", base: time.Second, maxBackoff: time.Hour, attempt: 0, want: time.Second},
+		{name: "first attempt", base: time.Second, maxBackoff: time.Hour, attempt: 1, want: time.Second},
+		{name: "second attempt", base: time.Second, maxBackoff: time.Hour, attempt: 2, want: 2 * time.Second},
+		{name: "fifth attempt", base: time.Second, maxBackoff: time.Hour, attempt: 5, want: 16 * time.Second},
+		{name: "capped by max backoff", base: time.Second, maxBackoff: time.Minute, attempt: 10, want: time.Minute},
+		{name: "equal max backoff", base: time.Second, maxBackoff: 8 * time.Second, attempt: 4, want: 8 * time.Second},
+		{name: "huge attempt", base: time.Second, maxBackoff: time.Hour, attempt: 1000, want: time.Hour},
+		{name: "no backoff", base: 0, maxBackoff: time.Hour, attempt: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryBackoff(tt.base, tt.maxBackoff, tt.attempt))
+		})
+	}
+}
@@ -58,22 +58,26 @@ func New(service factory.ServiceFactory, opts ...OptionFunc) factory.AppServerFa
 	server := &rpcServer{
 		service: service,
 		opt:     getDefaultOption(),
-		serverEngine: grpc.NewServer(
-			grpc.KeepaliveEnforcementPolicy(keepAliveEnforce),
-			grpc.KeepaliveParams(keepAliveServer),
-			grpc.UnaryInterceptor(
-				intercept.chainUnaryServer(
-					intercept.unaryServerTracerInterceptor,
-				),
-			),
-		),
 	}
-	reflection.Register(server.serverEngine)
 
 	for _, opt := range opts {
 		opt(&server.opt)
 	}
 
+	server.serverEngine = grpc.NewServer(
+		grpc.KeepaliveEnforcementPolicy(keepAliveEnforce),
+		grpc.KeepaliveParams(keepAliveServer),
+		grpc.UnaryInterceptor(
+			intercept.chainUnaryServer(
+				append(
+					[]grpc.UnaryServerInterceptor{intercept.unaryServerTracerInterceptor},
+					server.opt.unaryInterceptors...,
+				)...,
+			),
+		),
+	)
+	reflection.Register(server.serverEngine)
+
 	port := server.opt.tcpPort
 	var err error
 	server.listener, err = net.Listen("tcp", port)
@@ -4,11 +4,13 @@ import (
 	"fmt"
 
 	"github.com/mqdvi-dp/go-common/env"
+	"google.golang.org/grpc"
 )
 
 type option struct {
-	tcpPort   string
-	debugMode bool
+	tcpPort           string
+	debugMode         bool
+	unaryInterceptors []grpc.UnaryServerInterceptor
 }
 
 type OptionFunc func(*option)
@@ -33,3 +35,11 @@ func SetDebugMode(debugMode bool) OptionFunc {
 		o.debugMode = debugMode
 	}
 }
+
+// SetUnaryInterceptors option func, the interceptors are chained after the tracer interceptor, e.g.:
+// ratelimit.UnaryServerInterceptor
+func SetUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) OptionFunc {
+	return func(o *option) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/mqdvi-dp/go-common/factory/server/cron"
 	"github.com/mqdvi-dp/go-common/factory/server/kafka"
 	"github.com/mqdvi-dp/go-common/factory/server/nsq"
+	"github.com/mqdvi-dp/go-common/factory/server/redisstream"
+	"github.com/mqdvi-dp/go-common/factory/server/redistask"
 	"github.com/mqdvi-dp/go-common/factory/server/rest"
 	"github.com/mqdvi-dp/go-common/factory/server/rmq"
 	"github.com/mqdvi-dp/go-common/factory/server/rpc"
@@ -228,5 +230,49 @@ func (s *service) GetApplications() map[string]factory.AppServerFactory {
 		}
 	}
 
+	// is have worker handler for redis task?
+	if s.workerHandler[constants.RedisTask] != nil {
+		// check is redis task already registered
+		if _, ok := s.applications[constants.RedisTask.String()]; !ok {
+			if s.workerHandler[constants.RedisTask] != nil {
+				var redisTaskOptions []redistask.OptionFunc
+				if val, ok := s.workerHandlerOptions[constants.RedisTask]; ok {
+					if intfs, ok := val.([]interface{}); ok {
+						for _, intf := range intfs {
+							if opt, ok := intf.(redistask.OptionFunc); ok {
+								redisTaskOptions = append(redisTaskOptions, opt)
+							}
+						}
+					}
+				}
+
+				// initialized application redis task worker
+				s.applications[constants.RedisTask.String()] = redistask.NewWorker(s, redisTaskOptions...)
+			}
+		}
+	}
+
+	// is have worker handler for redis stream?
+	if s.workerHandler[constants.RedisStream] != nil {
+		// check is redis stream already registered
+		if _, ok := s.applications[constants.RedisStream.String()]; !ok {
+			if s.workerHandler[constants.RedisStream] != nil {
+				var redisStreamOptions []redisstream.OptionFunc
+				if val, ok := s.workerHandlerOptions[constants.RedisStream]; ok {
+					if intfs, ok := val.([]interface{}); ok {
+						for _, intf := range intfs {
+							if opt, ok := intf.(redisstream.OptionFunc); ok {
+								redisStreamOptions = append(redisStreamOptions, opt)
+							}
+						}
+					}
+				}
+
+				// initialized application redis stream consumer
+				s.applications[constants.RedisStream.String()] = redisstream.NewWorker(s, redisStreamOptions...)
+			}
+		}
+	}
+
 	return s.applications
 }
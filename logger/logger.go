@@ -27,6 +27,7 @@ type logTextFormatter struct {
 
 type logInterface interface {
 	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
 	Error(args ...interface{})
 	Debugf(format string, args ...interface{})
 	Debug(args ...interface{})
@@ -211,6 +212,47 @@ func (l *logger) ErrorWithFilename(ctx context.Context, file string, args ...int
 	value.Set(_LogMessage, lms)
 }
 
+func (l *logger) Warnf(ctx context.Context, format string, args ...interface{}) {
+	var (
+		lms  []LogMessage
+		file string
+	)
+
+	if env.GetBool("DEBUG", true) {
+		logT.Warnf(format, args...)
+	}
+
+	// get current log from context
+	value, ok := extract(ctx)
+	if !ok {
+		return
+	}
+
+	// get file and line
+	_, fileName, line, _ := runtime.Caller(1)
+	// default file
+	file = fmt.Sprintf("%s:%d", fileName, line)
+
+	files := strings.Split(fileName, "/")
+	if len(files) > 4 {
+		file = fmt.Sprintf("%s:%d", strings.Join(files[len(files)-3:], "/"), line)
+	}
+
+	tmp, ok := value.LoadAndDelete(_LogMessage)
+	if ok {
+		lms = tmp.([]LogMessage)
+	}
+
+	// append data to lms
+	lms = append(lms, LogMessage{
+		File:    file,
+		Level:   logrus.WarnLevel.String(),
+		Message: fmt.Sprintf(format, args...),
+	})
+
+	value.Set(_LogMessage, lms)
+}
+
 func (l *logger) Debugf(ctx context.Context, format string, args ...interface{}) {
 	var (
 		lms  []LogMessage
@@ -453,29 +495,41 @@ type logJsonFormatter struct {
 	logrus.JSONFormatter
 }
 
-// DB start logging
+// DB start logging, the arguments are masked with DATA_MASKED rules and the rules of the context
+// (see WithMaskedFields) are applied on Store. use DBContext when the arguments are sent to the tracer
 func DB(types DatabaseType, query string, args ...interface{}) Database {
-	startTime := time.Now()
+	return newDatabase(maskingFields(nil), types, query, args)
+}
 
-	// Convert the elements to strings and format them
-	var strElements []string
-	for _, v := range args {
-		val := fmt.Sprintf("%v", v)
-		if len(val) > 1000 {
-			val = "char argument too much"
-		}
+// DBContext start logging, the arguments are masked with DATA_MASKED rules and the rules of the context,
+// so Arguments sent to the tracer are masked the same as the stored log
+func DBContext(ctx context.Context, types DatabaseType, query string, args ...interface{}) Database {
+	d := newDatabase(maskingFields(ctx), types, query, args)
+	d.masked = true
 
-		strElements = append(strElements, val)
-	}
+	return d
+}
+
+func newDatabase(fields map[string]struct{}, types DatabaseType, query string, args []interface{}) Database {
+	startTime := time.Now()
+
+	// Convert the elements to strings and format them, the sensitive data is masked
+	strElements := maskArguments(types, fields, query, args)
 
 	return Database{
 		Type:      types,
 		Query:     query,
 		Arguments: strElements,
 		startTime: startTime,
+		args:      args,
 	}
 }
 
+// StartAt set the start time of the database log, used when the query is known after executed (e.g.: gorm callbacks)
+func (d *Database) StartAt(t time.Time) {
+	d.startTime = t
+}
+
 func RedBold(str interface{}) {
 	fmt.Printf("\x1b[31;1m%v\x1b[0m\n", str)
 }
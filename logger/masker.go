@@ -53,8 +53,36 @@ func maskToken(val string) string {
 	return splitAndMap(val, " ", helpers.SHA256)
 }
 
+// maskedFields returns the field names which are masked, set with env DATA_MASKED
+func maskedFields() []string {
+	return env.GetListString("DATA_MASKED", "password", "pin", "email", "phone_number", "username", "token", "authorization", "otp")
+}
+
+// maskField mask the value by the type of field
+func maskField(field, val string) string {
+	// because username can be as email and phone_number
+	// we need to check the value first
+	if strings.EqualFold(field, "username") {
+		if strings.Contains(val, "@") {
+			// will mask as email
+			return maskEmail(val)
+		}
+
+		// default is phone_number
+		return maskPhoneNumber(val)
+	}
+
+	mf, ok := mapMaskTypes[field]
+	if !ok {
+		// default
+		return maskValue(val)
+	}
+
+	return mf(val)
+}
+
 func MaskedCredentials(b []byte) []byte {
-	maskers := env.GetListString("DATA_MASKED", "password", "pin", "email", "phone_number", "username", "token", "authorization", "otp")
+	maskers := maskedFields()
 
 	// dd means dynamic-data
 	var dd = make(map[string]interface{})
@@ -91,28 +119,7 @@ func MaskedCredentials(b []byte) []byte {
 			continue
 		}
 
-		// because username can be as email and phone_number
-		// we need to check the value first
-		if strings.EqualFold(mask, "username") {
-			if strings.Contains(val, "@") {
-				// will mask as email
-				dd[mask] = maskEmail(val)
-				continue
-			}
-
-			// default is phone_number
-			dd[mask] = maskPhoneNumber(val)
-			continue
-		}
-
-		mf, ok := mapMaskTypes[mask]
-		if !ok {
-			// default
-			dd[mask] = maskValue(val)
-			continue
-		}
-
-		dd[mask] = mf(val)
+		dd[mask] = maskField(mask, val)
 	}
 
 	// marshal the data again
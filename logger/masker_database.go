@@ -0,0 +1,410 @@
+package logger
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	sqlComment     = regexp.MustCompile(`(?s)/\*.*?\*/|--[^\n]*`)
+	sqlString      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlPlaceholder = regexp.MustCompile(`\$(\d+)|\?|(?:^|[^:]):([A-Za-z_]\w*)`)
+	sqlInsert      = regexp.MustCompile(`(?is)insert\s+into\s+[\w."]+\s*\(([^)]*)\)\s*values\s*(.*)`)
+	sqlTuple       = regexp.MustCompile(`\(([^()]*)\)`)
+	sqlIn          = regexp.MustCompile(`(?i)([\w."]+)\s+(?:not\s+)?in\s*\(([^()]*)\)`)
+	sqlCompare     = regexp.MustCompile(`(?i)([\w."]+)\s*(?:=|<>|!=|>=|<=|>|<|\s(?:not\s+)?i?like\s)\s*(\$\d+|\?|:[A-Za-z_]\w*)`)
+)
+
+// masking is the per-query masking rule in the context
+type masking struct {
+	disabled bool
+	fields   []string
+}
+
+// WithMaskedFields mask the additional fields of the database arguments logged within the context,
+// the field is the column name, named parameter or segment of redis key, e.g.:
+//
+//	ctx = logger.WithMaskedFields(ctx, "nik", "mother_name")
+//	db.Exec(ctx, "INSERT INTO users (nik, mother_name) VALUES ($1, $2)", nik, motherName)
+func WithMaskedFields(ctx context.Context, fields ...string) context.Context {
+	m := masking{fields: fields}
+	if parent, ok := ctx.Value(maskKey).(masking); ok {
+		m.fields = append(append([]string{}, parent.fields...), fields...)
+	}
+
+	return context.WithValue(ctx, maskKey, m)
+}
+
+// WithoutMasking log the database arguments within the context as it is,
+// only use it for the queries without sensitive data
+func WithoutMasking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maskKey, masking{disabled: true})
+}
+
+var (
+	defaultMaskingOnce   sync.Once
+	defaultMaskingFields map[string]struct{}
+)
+
+// defaultFields returns the masked fields of env DATA_MASKED in lower case, the env is read once
+func defaultFields() map[string]struct{} {
+	defaultMaskingOnce.Do(func() {
+		defaultMaskingFields = make(map[string]struct{})
+		for _, field := range maskedFields() {
+			defaultMaskingFields[strings.ToLower(strings.TrimSpace(field))] = struct{}{}
+		}
+	})
+
+	return defaultMaskingFields
+}
+
+// maskingFields returns the masked fields in lower case, nil when the masking is disabled.
+// the returned map must not be modified
+func maskingFields(ctx context.Context) map[string]struct{} {
+	var m masking
+	if ctx != nil {
+		m, _ = ctx.Value(maskKey).(masking)
+	}
+	if m.disabled {
+		return nil
+	}
+	if len(m.fields) == 0 {
+		return defaultFields()
+	}
+
+	fields := make(map[string]struct{}, len(defaultFields())+len(m.fields))
+	for field := range defaultFields() {
+		fields[field] = struct{}{}
+	}
+	for _, field := range m.fields {
+		fields[strings.ToLower(strings.TrimSpace(field))] = struct{}{}
+	}
+
+	return fields
+}
+
+// formatArgument format the argument of database log
+func formatArgument(v interface{}) string {
+	val := fmt.Sprintf("%v", v)
+	if len(val) > 1000 {
+		val = "char argument too much"
+	}
+
+	return val
+}
+
+// maskArguments format and mask the arguments of database log
+func maskArguments(types DatabaseType, fields map[string]struct{}, query string, args []interface{}) []string {
+	var result []string
+	if fields == nil {
+		for _, v := range args {
+			result = append(result, formatArgument(v))
+		}
+
+		return result
+	}
+
+	var masked []interface{}
+	switch types {
+	case Redis:
+		masked = maskRedisArguments(fields, args)
+	default:
+		masked = maskSqlArguments(fields, query, args)
+	}
+
+	for _, v := range masked {
+		result = append(result, formatArgument(v))
+	}
+
+	return result
+}
+
+// maskSqlArguments mask the positional arguments by the column name of the placeholder,
+// or the named arguments (struct or map) by the parameter name and the column name of the parameter
+func maskSqlArguments(fields map[string]struct{}, query string, args []interface{}) []interface{} {
+	positional, named := sqlArgumentColumns(query)
+
+	masked := make([]interface{}, len(args))
+	for i, v := range args {
+		// named query, the argument is a struct, map or slice of them
+		if len(args) == 1 && len(named) > 0 {
+			if m, ok := maskNamedArgument(fields, named, v); ok {
+				masked[i] = m
+				continue
+			}
+		}
+
+		if column, ok := positional[i]; ok && isMaskedField(fields, column) {
+			masked[i] = maskArgument(column, v)
+			continue
+		}
+
+		masked[i] = maskJsonArgument(v)
+	}
+
+	return masked
+}
+
+// sqlArgumentColumns returns column name of the positional argument (by index) and the named parameter
+func sqlArgumentColumns(query string) (map[int]string, map[string]string) {
+	q := sqlComment.ReplaceAllString(query, " ")
+	q = sqlString.ReplaceAllString(q, "''")
+
+	// the ordinal of "?" placeholder is resolved by the position in the query
+	ordinals := make(map[int]int)
+	var ordinal int
+	for _, loc := range sqlPlaceholder.FindAllStringSubmatchIndex(q, -1) {
+		if q[loc[0]] == '?' {
+			ordinals[loc[0]] = ordinal
+			ordinal++
+		}
+	}
+
+	positional := make(map[int]string)
+	named := make(map[string]string)
+	bind := func(column, placeholder string, offset int) {
+		column = unqualified(column)
+		switch {
+		case strings.HasPrefix(placeholder, "$"):
+			if n, err := strconv.Atoi(placeholder[1:]); err == nil {
+				positional[n-1] = column
+			}
+		case placeholder == "?":
+			if n, ok := ordinals[offset]; ok {
+				positional[n] = column
+			}
+		case strings.HasPrefix(placeholder, ":"):
+			named[placeholder[1:]] = column
+		}
+	}
+
+	// INSERT INTO table (columns) VALUES (placeholders), (placeholders)
+	if loc := sqlInsert.FindStringSubmatchIndex(q); loc != nil {
+		columns := strings.Split(q[loc[2]:loc[3]], ",")
+		for _, tuple := range sqlTuple.FindAllStringSubmatchIndex(q[loc[4]:loc[5]], -1) {
+			start := loc[4] + tuple[2]
+			for j, item := range strings.Split(q[start:loc[4]+tuple[3]], ",") {
+				if j < len(columns) {
+					bind(columns[j], strings.TrimSpace(item), start+strings.Index(item, strings.TrimSpace(item)))
+				}
+				start += len(item) + 1
+			}
+		}
+	}
+
+	// column IN (placeholders)
+	for _, loc := range sqlIn.FindAllStringSubmatchIndex(q, -1) {
+		start := loc[4]
+		for _, item := range strings.Split(q[loc[4]:loc[5]], ",") {
+			bind(q[loc[2]:loc[3]], strings.TrimSpace(item), start+strings.Index(item, strings.TrimSpace(item)))
+			start += len(item) + 1
+		}
+	}
+
+	// column = placeholder, including SET and WHERE
+	for _, loc := range sqlCompare.FindAllStringSubmatchIndex(q, -1) {
+		bind(q[loc[2]:loc[3]], q[loc[4]:loc[5]], loc[4])
+	}
+
+	return positional, named
+}
+
+// unqualified returns the column name without table and quote, e.g.: u."pin" is pin
+func unqualified(column string) string {
+	column = strings.TrimSpace(column)
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+
+	return strings.ToLower(strings.Trim(column, `"`))
+}
+
+// maskNamedArgument mask the struct or map argument of named query, returns false when the argument is not named
+func maskNamedArgument(fields map[string]struct{}, named map[string]string, v interface{}) (interface{}, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, false
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		// bulk insert
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+
+		rows := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			row, ok := maskNamedArgument(fields, named, rv.Index(i).Interface())
+			if !ok {
+				return nil, false
+			}
+
+			rows = append(rows, row)
+		}
+
+		return rows, true
+	case reflect.Map, reflect.Struct:
+		values := namedValues(rv)
+		for param, val := range values {
+			field := strings.ToLower(param)
+			if column, ok := named[param]; ok && isMaskedField(fields, column) {
+				field = column
+			}
+
+			if isMaskedField(fields, field) {
+				values[param] = maskArgument(field, val)
+			}
+		}
+
+		return values, true
+	}
+
+	return nil, false
+}
+
+// namedValues returns the values of map or struct by the parameter name, the struct field is named by db tag
+func namedValues(rv reflect.Value) map[string]interface{} {
+	values := make(map[string]interface{})
+	if rv.Kind() == reflect.Map {
+		for _, key := range rv.MapKeys() {
+			values[fmt.Sprintf("%v", key.Interface())] = rv.MapIndex(key).Interface()
+		}
+
+		return values
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := strings.Split(sf.Tag.Get("db"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			// sqlx default name mapper
+			name = strings.ToLower(sf.Name)
+		}
+
+		values[name] = rv.Field(i).Interface()
+	}
+
+	return values
+}
+
+// maskRedisArguments mask the value when a segment of redis key is masked field (e.g.: otp:628123),
+// the map values (e.g.: hset) by the field name and the json values by the json key
+func maskRedisArguments(fields map[string]struct{}, args []interface{}) []interface{} {
+	masked := make([]interface{}, len(args))
+	if len(args) < 1 {
+		return masked
+	}
+	masked[0] = args[0]
+
+	var field string
+	if key, ok := args[0].(string); ok {
+		for _, segment := range strings.Split(key, ":") {
+			if isMaskedField(fields, segment) {
+				field = strings.ToLower(segment)
+				break
+			}
+		}
+	}
+
+	for i, v := range args[1:] {
+		// the value is the first argument after the key, the rest are expiration or score
+		if field != "" && i == 0 {
+			masked[i+1] = maskArgument(field, v)
+			continue
+		}
+
+		rv := reflect.Indirect(reflect.ValueOf(v))
+		if rv.IsValid() && rv.Kind() == reflect.Map {
+			values := namedValues(rv)
+			for name, val := range values {
+				if isMaskedField(fields, name) {
+					values[name] = maskArgument(strings.ToLower(name), val)
+				}
+			}
+
+			masked[i+1] = values
+			continue
+		}
+
+		masked[i+1] = maskJsonArgument(v)
+	}
+
+	return masked
+}
+
+func isMaskedField(fields map[string]struct{}, field string) bool {
+	_, ok := fields[strings.ToLower(field)]
+	return ok
+}
+
+// maskArgument mask the value as the field
+func maskArgument(field string, v interface{}) interface{} {
+	if valuer, ok := v.(driver.Valuer); ok {
+		if val, err := valuer.Value(); err == nil {
+			v = val
+		}
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return v
+	}
+	rv = reflect.Indirect(rv)
+
+	var val string
+	switch rv.Kind() {
+	case reflect.String:
+		val = rv.String()
+	case reflect.Slice:
+		if b, ok := rv.Interface().([]byte); ok {
+			val = string(b)
+			break
+		}
+
+		// e.g.: the arguments of IN
+		values := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, maskArgument(field, rv.Index(i).Interface()))
+		}
+		return values
+	default:
+		val = fmt.Sprintf("%v", rv.Interface())
+	}
+
+	if val == "" {
+		return val
+	}
+
+	return maskField(field, val)
+}
+
+// maskJsonArgument mask the json value (e.g.: jsonb column or cached response) with MaskedCredentials
+func maskJsonArgument(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(val), "{") {
+			return string(MaskedCredentials([]byte(val)))
+		}
+	case []byte:
+		if strings.HasPrefix(strings.TrimSpace(string(val)), "{") {
+			return string(MaskedCredentials(val))
+		}
+	}
+
+	return v
+}
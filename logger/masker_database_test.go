@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBContext(t *testing.T) {
+	query := "INSERT INTO users (nik, name) VALUES ($1, $2)"
+	ctx := WithMaskedFields(context.Background(), "nik")
+
+	// the field of the context is masked before the arguments are sent to the tracer
+	d := DBContext(ctx, Sql, query, "3201234567890001", "foo")
+	assert.NotEqual(t, "3201234567890001", d.Arguments[0])
+	assert.Equal(t, "foo", d.Arguments[1])
+
+	// DB only masks DATA_MASKED fields
+	d = DB(Sql, query, "3201234567890001", "foo")
+	assert.Equal(t, []string{"3201234567890001", "foo"}, d.Arguments)
+
+	// the masking is disabled within the context
+	d = DBContext(WithoutMasking(context.Background()), Sql, "SELECT * FROM users WHERE password = $1", "secret")
+	assert.Equal(t, []string{"secret"}, d.Arguments)
+}
@@ -41,6 +41,11 @@ func (d Database) Store(ctx context.Context) {
 	}
 
 	d.ExecutionTime = time.Since(d.startTime).Seconds()
+	// apply the masking rule of the context when it is not applied by DBContext, the appended arguments are kept
+	if _, ok = ctx.Value(maskKey).(masking); ok && !d.masked && len(d.Arguments) >= len(d.args) {
+		d.Arguments = append(maskArguments(d.Type, maskingFields(ctx), d.Query, d.args), d.Arguments[len(d.args):]...)
+	}
+	d.args = nil
 	db = append(db, d)
 	// set key into context with new value
 	value.Set(_Database, db)
@@ -20,6 +20,8 @@ type (
 const (
 	// LogKey is key context for handler type
 	LogKey Key = 31
+	// maskKey is key context for masking of database arguments
+	maskKey Key = 32
 
 	// Http is type for logging Http Request
 	Http HandlerType = "http"
@@ -31,6 +33,10 @@ const (
 	RabbitMQ HandlerType = "rabbitmq_consumer"
 	// Kafka is type for logging Kafka Consumer
 	Kafka HandlerType = "kafka_consumer"
+	// RedisTask is type for logging Redis delayed task consumer
+	RedisTask HandlerType = "redis_task_consumer"
+	// RedisStream is type for logging Redis streams consumer
+	RedisStream HandlerType = "redis_stream_consumer"
 	// Scheduler is type for logging Scheduler (cron job)
 	Scheduler HandlerType = "scheduler"
 
@@ -78,6 +84,14 @@ type Logger struct {
 	LogMessage    []LogMessage  `json:"debugging"`
 	OutgoingLog   []OutgoingLog `json:"outgoing_log"`
 	Database      []Database    `json:"database"`
+	Chain         *Chain        `json:"chain,omitempty"`
+}
+
+// Chain represents the state of a scheduler job chain.
+type Chain struct {
+	ChainId     string `json:"chain_id"`
+	Trigger     string `json:"trigger"`
+	TriggeredBy string `json:"triggered_by,omitempty"`
 }
 
 // OutgoingLog represents the state of a outgoing log request.
@@ -101,11 +115,13 @@ type LogMessage struct {
 
 // Database represents the state of a database log
 type Database struct {
-	Type          DatabaseType `json:"type"`
-	Query         string       `json:"query"`
-	Arguments     []string     `json:"arguments"`
-	startTime     time.Time    `json:"-"`
-	ExecutionTime float64      `json:"execution_time"`
+	Type          DatabaseType  `json:"type"`
+	Query         string        `json:"query"`
+	Arguments     []string      `json:"arguments"`
+	startTime     time.Time     `json:"-"`
+	args          []interface{} `json:"-"`
+	masked        bool          `json:"-"`
+	ExecutionTime float64       `json:"execution_time"`
 }
 
 // Locker is container data
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
 	"github.com/mqdvi-dp/go-common/env"
 	"github.com/mqdvi-dp/go-common/errs"
 	"github.com/mqdvi-dp/go-common/tracer"
@@ -87,7 +88,7 @@ func (m *middleware) blockedIp(ctx context.Context, originalIp string) error {
 	}
 
 	duration := env.GetDuration("DURATION_IP_BLOCKED", time.Hour*730)
-	err = m.redis.Set(ctx, fmt.Sprintf(prefixBlockedIp, originalIp), "1", duration)
+	err = m.redis.Set(ctx, fmt.Sprintf(prefixBlockedIp, originalIp), "1", rdc.WithTTL(duration))
 	if err != nil {
 		tracer.SetError(ctx, err)
 
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,7 +22,9 @@ import (
 	"github.com/mqdvi-dp/go-common/constants"
 	"github.com/mqdvi-dp/go-common/errs"
 	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/ratelimit"
 	"github.com/mqdvi-dp/go-common/response"
+	"github.com/mqdvi-dp/go-common/session"
 	"github.com/mqdvi-dp/go-common/tracer"
 	"github.com/mqdvi-dp/go-common/types"
 
@@ -85,6 +88,24 @@ func (m *middleware) HTTPAuth(c *gin.Context) {
 		return
 	}
 
+	// revocation lookup of the session by jti, the token without jti is rejected on strict.
+	// basic auth has no session
+	if m.sessions != nil && tokenType == bearer {
+		err = m.sessions.Validate(ctx, tc.GetUserUuid(), tc.GetJti())
+		if err != nil {
+			trace.SetError(err)
+			logger.Log.Error(ctx, err)
+
+			if errors.Is(err, session.ErrRevoked) {
+				response.Error(ctx, errs.NewErrorWithCodeErr(ErrInvalidSession, errs.SESSION_EXPIRED)).JSON(c)
+				return
+			}
+
+			response.Error(ctx, errs.NewErrorWithCodeErr(err, errs.REDIS_CONNECTION_ERROR)).JSON(c)
+			return
+		}
+	}
+
 	// set to local context
 	// sets user_id into context
 	c.Set(constants.UserId, tc.GetUserUuid())
@@ -385,16 +406,21 @@ func (m *middleware) HTTPRateLimit(limitRequest string) gin.HandlerFunc {
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 		}
 
-		// request to service rate-limit
-		err = m.validator.RateLimit(
-			ctx, types.RateLimit{
-				MaxRequest: maxRequest,
-				Duration:   duration,
-				UserId:     userId,
-				Method:     method,
-				Endpoint:   endpoint,
-			},
-		)
+		rl := types.RateLimit{
+			MaxRequest: maxRequest,
+			Duration:   duration,
+			UserId:     userId,
+			Method:     method,
+			Endpoint:   endpoint,
+		}
+
+		if m.limiter != nil {
+			// built-in redis rate limiter
+			err = m.httpRateLimitBuiltin(ctx, c, rl)
+		} else {
+			// request to service rate-limit
+			err = m.validator.RateLimit(ctx, rl)
+		}
 		if err != nil {
 			trace.SetError(err)
 			logger.Log.Error(ctx, err)
@@ -407,6 +433,29 @@ func (m *middleware) HTTPRateLimit(limitRequest string) gin.HandlerFunc {
 	}
 }
 
+// httpRateLimitBuiltin checks the rate limit with the built-in limiter and writes X-RateLimit-* and Retry-After headers
+func (m *middleware) httpRateLimitBuiltin(ctx context.Context, c *gin.Context, rl types.RateLimit) error {
+	result, err := m.limiter.Allow(ctx, ratelimit.Key(rl), rl.MaxRequest, rl.Duration)
+	if err != nil {
+		// the request is allowed when the limiter is fail open
+		if result.Allowed {
+			return nil
+		}
+
+		return err
+	}
+
+	for key, value := range result.Headers() {
+		c.Header(key, value)
+	}
+
+	if result.Allowed {
+		return nil
+	}
+
+	return errs.NewErrorWithCodeErr(ErrTooManyRequest, errs.TOO_MANY_REQUEST)
+}
+
 func (m *middleware) HTTPNotForPublic(c *gin.Context) {
 	ctx := c.Request.Context()
 
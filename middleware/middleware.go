@@ -6,6 +6,9 @@ import (
 
 	"github.com/mqdvi-dp/go-common/abstract"
 	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/env"
+	"github.com/mqdvi-dp/go-common/ratelimit"
+	"github.com/mqdvi-dp/go-common/session"
 	"github.com/mqdvi-dp/go-common/types"
 )
 
@@ -13,11 +16,43 @@ type middleware struct {
 	validator           abstract.AuthenticationValidator
 	authTypeCheckerFunc map[string]func(context.Context, string) (types.TokenClaim, error)
 	redis               rdc.Rdc
+	limiter             *ratelimit.Limiter
+	sessions            *session.Store
+}
+
+// OptionFunc option func for middleware
+type OptionFunc func(*middleware)
+
+// SetRateLimiter set the built-in redis rate limiter of HTTPRateLimit,
+// it's used instead of AuthenticationValidator RateLimit and writes X-RateLimit-* headers
+func SetRateLimiter(limiter *ratelimit.Limiter) OptionFunc {
+	return func(m *middleware) {
+		m.limiter = limiter
+	}
+}
+
+// SetSessionStore set the session store of HTTPAuth, the token with the revoked jti is rejected
+func SetSessionStore(sessions *session.Store) OptionFunc {
+	return func(m *middleware) {
+		m.sessions = sessions
+	}
 }
 
 // New initiate middleware
-func New(authValidator abstract.AuthenticationValidator, rds rdc.Rdc) *middleware {
+func New(authValidator abstract.AuthenticationValidator, rds rdc.Rdc, opts ...OptionFunc) *middleware {
 	m := &middleware{validator: authValidator, redis: rds}
+	// the built-in rate limiter is enabled by env RATE_LIMIT_BUILTIN, the algorithm is from env RATE_LIMIT_ALGORITHM
+	if rds != nil && env.GetBool("RATE_LIMIT_BUILTIN", false) {
+		m.limiter = ratelimit.New(rds)
+	}
+	// the revocation lookup of the session is enabled by env SESSION_REVOCATION_CHECK
+	if rds != nil && env.GetBool("SESSION_REVOCATION_CHECK", false) {
+		m.sessions = session.New(rds)
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
 
 	m.authTypeCheckerFunc = map[string]func(context.Context, string) (types.TokenClaim, error){
 		basic: func(ctx context.Context, token string) (types.TokenClaim, error) {
@@ -64,6 +64,14 @@ func (_m *logInterface) Println(args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Warnf provides a mock function with given fields: format, args
+func (_m *logInterface) Warnf(format string, args ...interface{}) {
+	var _ca []interface{}
+	_ca = append(_ca, format)
+	_ca = append(_ca, args...)
+	_m.Called(_ca...)
+}
+
 // WithField provides a mock function with given fields: key, value
 func (_m *logInterface) WithField(key string, value interface{}) *logrus.Entry {
 	ret := _m.Called(key, value)
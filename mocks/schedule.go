@@ -13,6 +13,44 @@ type Schedule struct {
 	mock.Mock
 }
 
+// Between provides a mock function with given fields: from, to
+func (_m *Schedule) Between(from time.Time, to time.Time) []time.Time {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Between")
+	}
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []time.Time); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	return r0
+}
+
+// Describe provides a mock function with given fields:
+func (_m *Schedule) Describe() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Describe")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Next provides a mock function with given fields: _a0
 func (_m *Schedule) Next(_a0 time.Time) time.Time {
 	ret := _m.Called(_a0)
@@ -49,6 +87,44 @@ func (_m *Schedule) NextInterval(_a0 time.Time) time.Duration {
 	return r0
 }
 
+// NextN provides a mock function with given fields: _a0, _a1
+func (_m *Schedule) NextN(_a0 time.Time, _a1 int) []time.Time {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextN")
+	}
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(time.Time, int) []time.Time); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	return r0
+}
+
+// Prev provides a mock function with given fields: _a0
+func (_m *Schedule) Prev(_a0 time.Time) time.Time {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prev")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(time.Time) time.Time); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
 // NewSchedule creates a new instance of Schedule. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSchedule(t interface {
@@ -0,0 +1,33 @@
+package monitoring
+
+import (
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var cacheCounter *prometheus.CounterVec
+
+// registerCache register the cache metrics
+func registerCache(appName string) {
+	// labels for cache counter, result is hit, miss, negative_hit, refresh or error
+	labelsCache := []string{"cache", "result"}
+	cacheCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "cache_requests_total",
+		Help:        "How many cache requests were processed by cache and result",
+		ConstLabels: prometheus.Labels{"application": appName},
+	}, labelsCache)
+
+	if err := prometheus.Register(cacheCounter); err != nil {
+		logger.Log.Fatalf("failed to register cache counter with an error %s", err)
+	}
+}
+
+// RecordCache record the result of cache request
+func RecordCache(cache, result string) {
+	// make sure an instance of counter registered
+	if cacheCounter == nil {
+		return
+	}
+
+	cacheCounter.WithLabelValues(cache, result).Inc()
+}
@@ -43,6 +43,10 @@ func New(appName string) {
 		if err := prometheus.Register(counter); err != nil {
 			logger.Log.Fatalf("failed to register counter with an error %s", err)
 		}
+
+		registerSql(appName)
+		registerCache(appName)
+		registerRedis(appName)
 	})
 }
 
@@ -0,0 +1,113 @@
+package monitoring
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var redisHistogram *prometheus.HistogramVec
+
+// registerRedis register the redis command metrics
+func registerRedis(appName string) {
+	// labels for redis histogram, command is the name of the command (e.g.: get) or pipeline and multi
+	labelsRedis := []string{"command", "state"}
+	redisHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:        "redis_command_duration",
+		Help:        "How long it took to execute redis commands by command",
+		ConstLabels: prometheus.Labels{"application": appName},
+		Buckets:     []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, labelsRedis)
+
+	if err := prometheus.Register(redisHistogram); err != nil {
+		logger.Log.Fatalf("failed to register redis histogram with an error %s", err)
+	}
+}
+
+// RecordRedis record duration of the redis command, redis.Nil is not an error
+func RecordRedis(command string, err error, duration time.Duration) {
+	// make sure an instance of histogram registered
+	if redisHistogram == nil {
+		return
+	}
+
+	state := successState
+	if err != nil && !errors.Is(err, redis.Nil) {
+		state = systemErrorState
+	}
+
+	redisHistogram.WithLabelValues(command, state).Observe(duration.Seconds())
+}
+
+// redisStatsCollector collect the connection pool stats of the redis client
+type redisStatsCollector struct {
+	client redis.UniversalClient
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+func newRedisStatsCollector(name string, client redis.UniversalClient) *redisStatsCollector {
+	labels := prometheus.Labels{"db_name": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("go", "redis", metric), help, nil, labels)
+	}
+
+	return &redisStatsCollector{
+		client:     client,
+		hits:       desc("pool_hits_total", "The number of times free connection was found in the pool."),
+		misses:     desc("pool_misses_total", "The number of times free connection was not found in the pool."),
+		timeouts:   desc("pool_timeouts_total", "The number of times a wait timeout occurred."),
+		totalConns: desc("pool_total_connections", "The number of total connections in the pool."),
+		idleConns:  desc("pool_idle_connections", "The number of idle connections in the pool."),
+		staleConns: desc("pool_stale_connections_total", "The number of stale connections removed from the pool."),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *redisStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+// Collect implements prometheus.Collector
+func (c *redisStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
+
+// RegisterRedisStats register the connection pool stats (hits, misses, timeouts, total, idle and stale connections)
+// of the redis client, name is used as db_name label to differentiate the clients
+func RegisterRedisStats(name string, client redis.UniversalClient) {
+	err := prometheus.Register(newRedisStatsCollector(name, client))
+	if err == nil {
+		return
+	}
+
+	// the other client with the same name is already registered, the stats of this client are not recorded
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		logger.Red(fmt.Sprintf("redis stats %s is already registered, set distinct service name of the redis", name))
+		return
+	}
+
+	logger.Red(fmt.Sprintf("failed to register redis stats %s with an error %s", name, err))
+}
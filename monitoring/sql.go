@@ -0,0 +1,64 @@
+package monitoring
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var sqlHistogram *prometheus.HistogramVec
+
+// registerSql register the sql query metrics
+func registerSql(appName string) {
+	// labels for sql histogram, query is the normalized fingerprint
+	labelsSql := []string{"operation", "query", "state"}
+	sqlHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:        "sql_query_duration",
+		Help:        "How long it took to execute sql queries by operation and query fingerprint",
+		ConstLabels: prometheus.Labels{"application": appName},
+		Buckets:     []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, labelsSql)
+
+	if err := prometheus.Register(sqlHistogram); err != nil {
+		logger.Log.Fatalf("failed to register sql histogram with an error %s", err)
+	}
+}
+
+// RecordSql record duration of the sql query, operation is the sql command (e.g.: SELECT)
+// and fingerprint is the normalized query without literal value
+func RecordSql(operation, fingerprint string, err error, duration time.Duration) {
+	// make sure an instance of histogram registered
+	if sqlHistogram == nil {
+		return
+	}
+
+	state := successState
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		state = systemErrorState
+	}
+
+	sqlHistogram.WithLabelValues(operation, fingerprint, state).Observe(duration.Seconds())
+}
+
+// RegisterSqlStats register the connection pool stats (open, in use, idle, wait count and wait duration) of the database,
+// name is used as db_name label to differentiate the databases
+func RegisterSqlStats(name string, db *sql.DB) {
+	err := prometheus.Register(collectors.NewDBStatsCollector(db, name))
+	if err == nil {
+		return
+	}
+
+	// the other database with the same name is already registered, the stats of this database are not recorded
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		logger.Red(fmt.Sprintf("sql stats %s is already registered, set distinct name of the database", name))
+		return
+	}
+
+	logger.Red(fmt.Sprintf("failed to register sql stats %s with an error %s", name, err))
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/errs"
+	"github.com/mqdvi-dp/go-common/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// KeyFunc returns the key of the grpc request, the request is not limited when the key is empty
+type KeyFunc func(ctx context.Context, fullMethod string) string
+
+// PeerKey is the default KeyFunc, the key is the full method and the ip of the client
+func PeerKey(ctx context.Context, fullMethod string) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return fullMethod
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	return fullMethod + ":" + host
+}
+
+// UnaryServerInterceptor limits the grpc requests, limit requests every period for every key.
+// the x-ratelimit-* headers are sent and TOO_MANY_REQUEST (ResourceExhausted) is returned when the limit exceeded.
+// when the limit is invalid GENERAL_ERROR is returned, and when redis is failed the request is allowed on fail open,
+// otherwise REDIS_CONNECTION_ERROR (Unavailable) is returned
+func UnaryServerInterceptor(l *Limiter, limit int, period time.Duration, keyFunc KeyFunc) grpc.UnaryServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = PeerKey
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		key := keyFunc(ctx, info.FullMethod)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		result, err := l.Allow(ctx, key, limit, period)
+		if err != nil {
+			// the request is allowed when the limiter is fail open
+			if result.Allowed {
+				return handler(ctx, req)
+			}
+
+			if errors.Is(err, ErrInvalidLimit) {
+				return nil, errs.NewErrorWithCodeErr(err, errs.GENERAL_ERROR)
+			}
+
+			return nil, errs.NewError(err, http.StatusServiceUnavailable, errs.REDIS_CONNECTION_ERROR.Code(), errs.REDIS_CONNECTION_ERROR.Message())
+		}
+
+		md := metadata.MD{}
+		for k, v := range result.Headers() {
+			md.Set(strings.ToLower(k), v)
+		}
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			logger.Log.Errorf(ctx, "failed to set rate limit header: %s", err)
+		}
+
+		if !result.Allowed {
+			return nil, errs.NewErrorWithCodeErr(ErrLimited, errs.TOO_MANY_REQUEST)
+		}
+
+		return handler(ctx, req)
+	}
+}
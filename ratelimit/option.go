@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"github.com/mqdvi-dp/go-common/env"
+)
+
+type option struct {
+	algorithm Algorithm
+	prefix    string
+	failOpen  bool
+}
+
+// OptionFunc option func for rate limiter
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		algorithm: Algorithm(env.GetString("RATE_LIMIT_ALGORITHM", string(SlidingWindowCounter))),
+		prefix:    env.GetString("RATE_LIMIT_PREFIX", "rate_limit"),
+		failOpen:  env.GetBool("RATE_LIMIT_FAIL_OPEN", true),
+	}
+}
+
+// SetAlgorithm set the algorithm of the limiter, default is sliding window counter
+func SetAlgorithm(algorithm Algorithm) OptionFunc {
+	return func(o *option) {
+		o.algorithm = algorithm
+	}
+}
+
+// SetPrefix set the prefix of the redis keys
+func SetPrefix(prefix string) OptionFunc {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+// SetFailOpen set the request is allowed when redis returns an error, default is true
+func SetFailOpen(failOpen bool) OptionFunc {
+	return func(o *option) {
+		o.failOpen = failOpen
+	}
+}
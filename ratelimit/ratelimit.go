@@ -0,0 +1,224 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/errs"
+	"github.com/mqdvi-dp/go-common/logger"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/mqdvi-dp/go-common/types"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrLimited returned when the request exceed the limit
+	ErrLimited = errors.New("rate limit exceeded")
+	// ErrInvalidLimit returned when the limit, the period or the cost is invalid
+	ErrInvalidLimit = errors.New("invalid rate limit")
+)
+
+// Algorithm of the rate limiter
+type Algorithm string
+
+const (
+	// FixedWindow counts the requests in fixed window, cheapest but allows 2x burst on the edge of the windows
+	FixedWindow Algorithm = "fixed_window"
+	// SlidingWindowLog stores every request in the window, exact but the memory grows with the limit
+	SlidingWindowLog Algorithm = "sliding_window_log"
+	// SlidingWindowCounter approximates the sliding window with the counters of current and previous window
+	SlidingWindowCounter Algorithm = "sliding_window_counter"
+	// TokenBucket refills the tokens continuously, allows burst up to the limit
+	TokenBucket Algorithm = "token_bucket"
+	// GCRA is generic cell rate algorithm, spreads the requests evenly with burst up to the limit
+	GCRA Algorithm = "gcra"
+)
+
+var scripts = map[Algorithm]*redis.Script{
+	FixedWindow:          fixedWindow,
+	SlidingWindowLog:     slidingWindowLog,
+	SlidingWindowCounter: slidingWindowCounter,
+	TokenBucket:          tokenBucket,
+	GCRA:                 gcra,
+}
+
+func (a Algorithm) String() string {
+	return string(a)
+}
+
+// Result of the rate limiter
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter duration until the limiter is fully reset
+	ResetAfter time.Duration
+	// RetryAfter duration until the next request is allowed, 0 when allowed
+	RetryAfter time.Duration
+}
+
+// Headers returns X-RateLimit-* headers and Retry-After when the request is not allowed,
+// the duration is in seconds (rounded up)
+func (r Result) Headers() map[string]string {
+	headers := map[string]string{
+		"X-RateLimit-Limit":     strconv.Itoa(r.Limit),
+		"X-RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"X-RateLimit-Reset":     strconv.FormatInt(seconds(r.ResetAfter), 10),
+	}
+	if !r.Allowed {
+		retryAfter := seconds(r.RetryAfter)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		headers["Retry-After"] = strconv.FormatInt(retryAfter, 10)
+	}
+
+	return headers
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// Limiter is rate limiter on top of redis, every algorithm is an atomic lua script
+type Limiter struct {
+	rdc rdc.Rdc
+	opt option
+}
+
+// New create new rate limiter with redis client
+func New(client rdc.Rdc, opts ...OptionFunc) *Limiter {
+	l := &Limiter{rdc: client, opt: getDefaultOption()}
+	for _, opt := range opts {
+		opt(&l.opt)
+	}
+
+	if _, ok := scripts[l.opt.algorithm]; !ok {
+		logger.Log.Warnf(context.Background(), "unknown rate limit algorithm %s, use %s", l.opt.algorithm, SlidingWindowCounter)
+		l.opt.algorithm = SlidingWindowCounter
+	}
+
+	return l
+}
+
+// Allow checks one request of the key is allowed, limit requests every period
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, period time.Duration) (Result, error) {
+	return l.AllowN(ctx, key, limit, period, 1)
+}
+
+// AllowN checks n requests of the key are allowed at once, limit requests every period.
+// when redis returns an error, the request is allowed on fail open and the error is returned
+func (l *Limiter) AllowN(ctx context.Context, key string, limit int, period time.Duration, n int) (result Result, err error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "RateLimit:Allow")
+	defer trace.Finish()
+
+	trace.SetTag("algorithm", l.opt.algorithm)
+	trace.SetTag("key", key)
+	trace.Log("limit", limit)
+	trace.Log("period", period)
+	trace.Log("n", n)
+
+	result = Result{Limit: limit}
+	if limit < 1 || period < time.Millisecond || n < 1 {
+		err = fmt.Errorf("%w %d@%s with cost %d", ErrInvalidLimit, limit, period, n)
+		trace.SetError(err)
+		return
+	}
+
+	args := []interface{}{limit, period.Milliseconds(), n}
+	if l.opt.algorithm == SlidingWindowLog {
+		args = append(args, uuid.NewString())
+	}
+
+	reply, err := l.rdc.EvalScript(ctx, scripts[l.opt.algorithm], []string{l.key(key)}, args...)
+	if err == nil {
+		result, err = parseResult(limit, reply)
+	}
+	if err != nil {
+		trace.SetError(err)
+		logger.Log.Errorf(ctx, "failed to check rate limit %s: %s", key, err)
+
+		result = Result{Allowed: l.opt.failOpen, Limit: limit, Remaining: limit}
+		return
+	}
+
+	trace.SetTag("allowed", result.Allowed)
+	trace.Log("remaining", result.Remaining)
+	trace.Log("retry_after", result.RetryAfter)
+
+	return result, nil
+}
+
+// Wait blocks until one request of the key is allowed or the context is done, used by the workers
+func (l *Limiter) Wait(ctx context.Context, key string, limit int, period time.Duration) error {
+	for {
+		result, err := l.Allow(ctx, key, limit, period)
+		if result.Allowed {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(result.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RateLimit checks the limit of the user to access the endpoint, it can be used as the implementation of
+// abstract.AuthenticationValidator RateLimit. returns error with status code 429 when the limit exceeded
+func (l *Limiter) RateLimit(ctx context.Context, rl types.RateLimit) error {
+	result, err := l.Allow(ctx, Key(rl), rl.MaxRequest, rl.Duration)
+	if !result.Allowed {
+		if err != nil {
+			return err
+		}
+
+		return errs.NewErrorWithCodeErr(ErrLimited, errs.TOO_MANY_REQUEST)
+	}
+
+	return nil
+}
+
+// Key returns the key of the rate limit, {method}:{endpoint}:{user id}
+func Key(rl types.RateLimit) string {
+	return strings.Join([]string{rl.Method, rl.Endpoint, rl.UserId}, ":")
+}
+
+func (l *Limiter) key(key string) string {
+	return fmt.Sprintf("%s:%s:%s", l.opt.prefix, l.opt.algorithm, key)
+}
+
+func parseResult(limit int, reply interface{}) (Result, error) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected reply of rate limit script: %v", reply)
+	}
+
+	var ints [4]int64
+	for i, v := range values {
+		if ints[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("unexpected reply of rate limit script: %v", reply)
+		}
+	}
+
+	return Result{
+		Allowed:    ints[0] == 1,
+		Limit:      limit,
+		Remaining:  int(ints[1]),
+		ResetAfter: time.Duration(ints[2]) * time.Millisecond,
+		RetryAfter: time.Duration(ints[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mqdvi-dp/go-common/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   interface{}
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:  "allowed",
+			reply: []interface{}{int64(1), int64(9), int64(60000), int64(0)},
+			want:  Result{Allowed: true, Limit: 10, Remaining: 9, ResetAfter: time.Minute},
+		},
+		{
+			name:  "denied",
+			reply: []interface{}{int64(0), int64(0), int64(60000), int64(1500)},
+			want:  Result{Allowed: false, Limit: 10, Remaining: 0, ResetAfter: time.Minute, RetryAfter: 1500 * time.Millisecond},
+		},
+		{
+			name:    "not a list",
+			reply:   int64(1),
+			wantErr: true,
+		},
+		{
+			name:    "missing values",
+			reply:   []interface{}{int64(1), int64(9)},
+			wantErr: true,
+		},
+		{
+			name:    "not an integer",
+			reply:   []interface{}{int64(1), "9", int64(60000), int64(0)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseResult(10, tt.reply)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
+
+func TestResultHeaders(t *testing.T) {
+	allowed := Result{Allowed: true, Limit: 10, Remaining: 9, ResetAfter: 1500 * time.Millisecond}
+	assert.Equal(t, map[string]string{
+		"X-RateLimit-Limit":     "10",
+		"X-RateLimit-Remaining": "9",
+		"X-RateLimit-Reset":     "2",
+	}, allowed.Headers())
+
+	// retry after is at least 1 second
+	denied := Result{Limit: 10, ResetAfter: time.Minute, RetryAfter: 100 * time.Millisecond}
+	assert.Equal(t, map[string]string{
+		"X-RateLimit-Limit":     "10",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "60",
+		"Retry-After":           "1",
+	}, denied.Headers())
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "GET:/v1/users:user-1", Key(types.RateLimit{Method: "GET", Endpoint: "/v1/users", UserId: "user-1"}))
+}
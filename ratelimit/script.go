@@ -0,0 +1,145 @@
+package ratelimit
+
+import "github.com/mqdvi-dp/go-common/config/database/rdc"
+
+// every script returns {allowed, remaining, reset after (ms), retry after (ms)}
+// KEYS[1] is the key of the limiter, ARGV[1] limit, ARGV[2] period (ms), ARGV[3] cost of the request.
+// the current time is taken from redis, so the result is not affected by clock skew of the instances
+const scriptNow = `
+if redis.replicate_commands then redis.replicate_commands() end
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+`
+
+// fixedWindow counts the requests in the window started on the first request
+var fixedWindow = rdc.NewScript(scriptNow + `
+local current = tonumber(redis.call('GET', key) or '0')
+local ttl = redis.call('PTTL', key)
+if ttl < 0 then ttl = period end
+
+if current + n > limit then
+	return {0, math.max(0, limit - current), ttl, ttl}
+end
+
+current = redis.call('INCRBY', key, n)
+if redis.call('PTTL', key) < 0 then
+	redis.call('PEXPIRE', key, period)
+end
+
+return {1, math.max(0, limit - current), ttl, 0}
+`)
+
+// slidingWindowLog stores the timestamp of every request in sorted set, ARGV[4] is unique id of the request
+var slidingWindowLog = rdc.NewScript(scriptNow + `
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - period)
+local count = redis.call('ZCARD', key)
+
+if count + n > limit then
+	-- wait until enough requests are out of the window
+	local retry = period
+	local idx = count + n - limit - 1
+	if idx < count then
+		local entry = redis.call('ZRANGE', key, idx, idx, 'WITHSCORES')
+		if entry[2] then retry = tonumber(entry[2]) + period - now end
+	end
+	local reset = period
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	if oldest[2] then reset = tonumber(oldest[2]) + period - now end
+	return {0, math.max(0, limit - count), reset, math.max(1, retry)}
+end
+
+for i = 1, n do
+	redis.call('ZADD', key, now, now .. ':' .. ARGV[4] .. ':' .. i)
+end
+redis.call('PEXPIRE', key, period)
+
+local reset = period
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then reset = tonumber(oldest[2]) + period - now end
+
+return {1, limit - count - n, reset, 0}
+`)
+
+// slidingWindowCounter weights the counter of the previous window by the overlap with the sliding window,
+// the counters are the fields of the hash with the window number
+var slidingWindowCounter = rdc.NewScript(scriptNow + `
+local window = math.floor(now / period)
+local elapsed = now - window * period
+local weight = (period - elapsed) / period
+
+local previous = tonumber(redis.call('HGET', key, tostring(window - 1)) or '0')
+local current = tonumber(redis.call('HGET', key, tostring(window)) or '0')
+local estimated = previous * weight + current
+
+if estimated + n > limit then
+	local retry = period - elapsed
+	if current + n <= limit and previous > 0 then
+		-- wait until the weight of the previous window is small enough
+		local w = (limit - current - n) / previous
+		retry = math.ceil((period - w * period) - elapsed)
+	end
+	return {0, math.max(0, math.floor(limit - estimated)), period - elapsed, math.max(1, retry)}
+end
+
+redis.call('HINCRBY', key, tostring(window), n)
+redis.call('HDEL', key, tostring(window - 2))
+redis.call('PEXPIRE', key, period * 2)
+
+return {1, math.max(0, math.floor(limit - estimated - n)), period - elapsed, 0}
+`)
+
+// tokenBucket refills limit tokens every period, the capacity of the bucket is the limit
+var tokenBucket = rdc.NewScript(scriptNow + `
+local rate = limit / period
+local data = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = limit
+	ts = now
+end
+
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= n then
+	tokens = tokens - n
+	allowed = 1
+else
+	retry = math.ceil((n - tokens) / rate)
+end
+
+-- the bucket is full after reset, the key is expired since it is the same as no key
+local reset = math.ceil((limit - tokens) / rate)
+redis.call('HSET', key, 'tokens', string.format('%.6f', tokens), 'ts', string.format('%d', now))
+redis.call('PEXPIRE', key, math.max(1, reset))
+
+return {allowed, math.floor(tokens), reset, retry}
+`)
+
+// gcra is generic cell rate algorithm, it stores only the theoretical arrival time (tat) of the next request.
+// the emission interval is period / limit and the burst is the limit
+var gcra = rdc.NewScript(scriptNow + `
+local interval = period / limit
+local tat = tonumber(redis.call('GET', key) or '0')
+tat = math.max(tat, now)
+
+local newTat = tat + n * interval
+local allowAt = newTat - period
+
+if allowAt > now then
+	local remaining = math.floor((now - (tat - period)) / interval)
+	return {0, math.max(0, remaining), math.ceil(tat - now), math.ceil(allowAt - now)}
+end
+
+local reset = math.ceil(newTat - now)
+redis.call('SET', key, string.format('%.3f', newTat), 'PX', math.max(1, reset))
+
+local remaining = math.floor((now - allowAt) / interval)
+return {1, math.max(0, remaining), reset, 0}
+`)
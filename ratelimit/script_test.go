@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testLimit  = 3
+	testPeriod = time.Minute
+)
+
+// step is one request to the limiter after the time advanced
+type step struct {
+	advance time.Duration
+	n       int
+	want    Result
+}
+
+func allowed(remaining int, reset time.Duration) Result {
+	return Result{Allowed: true, Limit: testLimit, Remaining: remaining, ResetAfter: reset}
+}
+
+func denied(remaining int, reset, retry time.Duration) Result {
+	return Result{Limit: testLimit, Remaining: remaining, ResetAfter: reset, RetryAfter: retry}
+}
+
+func TestScripts(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm Algorithm
+		steps     []step
+	}{
+		{
+			name:      "fixed window",
+			algorithm: FixedWindow,
+			steps: []step{
+				{n: 1, want: allowed(2, 60*time.Second)},
+				{n: 1, want: allowed(1, 60*time.Second)},
+				{n: 1, want: allowed(0, 60*time.Second)},
+				{n: 1, want: denied(0, 60*time.Second, 60*time.Second)},
+				{advance: 30 * time.Second, n: 1, want: denied(0, 30*time.Second, 30*time.Second)},
+				{advance: 30 * time.Second, n: 1, want: allowed(2, 60*time.Second)},
+			},
+		},
+		{
+			name:      "fixed window with cost",
+			algorithm: FixedWindow,
+			steps: []step{
+				{n: 2, want: allowed(1, 60*time.Second)},
+				{n: 2, want: denied(1, 60*time.Second, 60*time.Second)},
+				{n: 1, want: allowed(0, 60*time.Second)},
+			},
+		},
+		{
+			name:      "sliding window log",
+			algorithm: SlidingWindowLog,
+			steps: []step{
+				{n: 1, want: allowed(2, 60*time.Second)},
+				{advance: 10 * time.Second, n: 1, want: allowed(1, 50*time.Second)},
+				{advance: 10 * time.Second, n: 1, want: allowed(0, 40*time.Second)},
+				// wait until the first request is out of the window
+				{advance: 10 * time.Second, n: 1, want: denied(0, 30*time.Second, 30*time.Second)},
+				{advance: 30 * time.Second, n: 1, want: allowed(0, 10*time.Second)},
+				// wait until the first two requests are out of the window
+				{n: 2, want: denied(0, 10*time.Second, 20*time.Second)},
+			},
+		},
+		{
+			name:      "sliding window log with cost",
+			algorithm: SlidingWindowLog,
+			steps: []step{
+				{n: 2, want: allowed(1, 60*time.Second)},
+				{n: 2, want: denied(1, 60*time.Second, 60*time.Second)},
+				{advance: 60 * time.Second, n: 2, want: allowed(1, 60*time.Second)},
+			},
+		},
+		{
+			name:      "sliding window counter",
+			algorithm: SlidingWindowCounter,
+			steps: []step{
+				{n: 1, want: allowed(2, 60*time.Second)},
+				{n: 1, want: allowed(1, 60*time.Second)},
+				{n: 1, want: allowed(0, 60*time.Second)},
+				{n: 1, want: denied(0, 60*time.Second, 60*time.Second)},
+				// the previous window is full, wait until the weight is 2/3
+				{advance: 60 * time.Second, n: 1, want: denied(0, 60*time.Second, 20*time.Second)},
+				{advance: 20 * time.Second, n: 1, want: allowed(0, 40*time.Second)},
+				// wait until the weight is 1/3
+				{n: 1, want: denied(0, 40*time.Second, 20*time.Second)},
+				{advance: 20 * time.Second, n: 1, want: allowed(0, 20*time.Second)},
+			},
+		},
+		{
+			name:      "sliding window counter with cost",
+			algorithm: SlidingWindowCounter,
+			steps: []step{
+				{n: 2, want: allowed(1, 60*time.Second)},
+				{n: 2, want: denied(1, 60*time.Second, 60*time.Second)},
+				{n: 1, want: allowed(0, 60*time.Second)},
+			},
+		},
+		{
+			name:      "token bucket",
+			algorithm: TokenBucket,
+			steps: []step{
+				// one token is refilled every 20 seconds
+				{n: 1, want: allowed(2, 20*time.Second)},
+				{n: 2, want: allowed(0, 60*time.Second)},
+				{n: 1, want: denied(0, 60*time.Second, 20*time.Second)},
+				{advance: 20 * time.Second, n: 1, want: allowed(0, 60*time.Second)},
+				{advance: 10 * time.Second, n: 1, want: denied(0, 50*time.Second, 10*time.Second)},
+				// the bucket is full again
+				{advance: 50 * time.Second, n: 3, want: allowed(0, 60*time.Second)},
+			},
+		},
+		{
+			name:      "gcra",
+			algorithm: GCRA,
+			steps: []step{
+				// the emission interval is 20 seconds
+				{n: 1, want: allowed(2, 20*time.Second)},
+				{n: 1, want: allowed(1, 40*time.Second)},
+				{n: 1, want: allowed(0, 60*time.Second)},
+				{n: 1, want: denied(0, 60*time.Second, 20*time.Second)},
+				{advance: 20 * time.Second, n: 1, want: allowed(0, 60*time.Second)},
+				{advance: 40 * time.Second, n: 3, want: denied(2, 20*time.Second, 20*time.Second)},
+				{n: 2, want: allowed(0, 60*time.Second)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := miniredis.RunT(t)
+			// the scripts use the time of redis, start on the beginning of the window
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			m.SetTime(now)
+
+			l := New(rdc.New(redis.NewClient(&redis.Options{Addr: m.Addr()})), SetAlgorithm(tt.algorithm))
+			for i, s := range tt.steps {
+				if s.advance > 0 {
+					now = now.Add(s.advance)
+					m.SetTime(now)
+					m.FastForward(s.advance)
+				}
+
+				result, err := l.AllowN(context.Background(), "user-1", testLimit, testPeriod, s.n)
+				assert.NoError(t, err)
+				assert.Equal(t, s.want, result, "step %d", i+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"time"
+
+	"github.com/mqdvi-dp/go-common/env"
+)
+
+type option struct {
+	prefix      string
+	ttl         time.Duration
+	maxSessions int
+	strict      bool
+}
+
+// OptionFunc option func for session store
+type OptionFunc func(*option)
+
+func getDefaultOption() option {
+	return option{
+		prefix:      env.GetString("SESSION_PREFIX", "session"),
+		ttl:         env.GetDuration("SESSION_TTL", 30*24*time.Hour),
+		maxSessions: env.GetInt("SESSION_MAX_CONCURRENT", 5),
+		strict:      env.GetBool("SESSION_STRICT", false),
+	}
+}
+
+// SetPrefix set the prefix of the redis keys
+func SetPrefix(prefix string) OptionFunc {
+	return func(o *option) {
+		o.prefix = prefix
+	}
+}
+
+// SetTTL set the lifetime of the session, it should be the same as the lifetime of the refresh token
+func SetTTL(ttl time.Duration) OptionFunc {
+	return func(o *option) {
+		o.ttl = ttl
+	}
+}
+
+// SetMaxSessions set the maximum concurrent sessions of the user, the oldest sessions are revoked
+// when the new session is created. less than 1 is unlimited
+func SetMaxSessions(maxSessions int) OptionFunc {
+	return func(o *option) {
+		o.maxSessions = maxSessions
+	}
+}
+
+// SetStrict set the token is revoked when the session does not exist or the token has no jti,
+// otherwise only the revoked sessions are rejected (default)
+func SetStrict(strict bool) OptionFunc {
+	return func(o *option) {
+		o.strict = strict
+	}
+}
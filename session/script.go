@@ -0,0 +1,133 @@
+package session
+
+import "github.com/mqdvi-dp/go-common/config/database/rdc"
+
+// KEYS[1] is the sorted set of the sessions of the user (score is the expiry in unix millisecond),
+// KEYS[2] is the hash of the device id and the session id. ARGV[1] is the key prefix of the user, e.g.: session:{user}:
+// ARGV[2] is the current time in unix millisecond. the keys of the user share the hashtag, so it is safe in the cluster
+const scriptRevoke = `
+local index = KEYS[1]
+local devices = KEYS[2]
+local prefix = ARGV[1]
+local now = tonumber(ARGV[2])
+
+-- revoke delete the session and mark the session id as revoked until the session is expired
+local function revoke(id)
+	local score = redis.call('ZSCORE', index, id)
+	redis.call('DEL', prefix .. 's:' .. id)
+	redis.call('ZREM', index, id)
+
+	local fields = redis.call('HGETALL', devices)
+	for i = 1, #fields, 2 do
+		if fields[i + 1] == id then
+			redis.call('HDEL', devices, fields[i])
+		end
+	end
+
+	if score then
+		local ttl = math.ceil(tonumber(score) - now)
+		if ttl > 0 then
+			redis.call('SET', prefix .. 'r:' .. id, '1', 'PX', ttl)
+		end
+	end
+end
+
+-- expire the index and the devices with the last expired session
+local function expire()
+	local last = redis.call('ZRANGE', index, -1, -1, 'WITHSCORES')
+	if last[2] then
+		local ttl = math.ceil(tonumber(last[2]) - now)
+		if ttl > 0 then
+			redis.call('PEXPIRE', index, ttl)
+			redis.call('PEXPIRE', devices, ttl)
+			return
+		end
+	end
+
+	redis.call('DEL', index, devices)
+end
+
+-- remove the expired sessions
+for _, id in ipairs(redis.call('ZRANGEBYSCORE', index, '-inf', now)) do
+	revoke(id)
+end
+`
+
+// create ARGV[3] session id, ARGV[4] session data, ARGV[5] expiry in unix millisecond, ARGV[6] maximum sessions,
+// ARGV[7] device id. the session of the same device and the oldest sessions over the maximum are revoked.
+// returns the revoked session ids
+var create = rdc.NewScript(scriptRevoke + `
+local id = ARGV[3]
+local expiresAt = tonumber(ARGV[5])
+local max = tonumber(ARGV[6])
+local device = ARGV[7]
+local revoked = {}
+
+if device ~= '' then
+	local old = redis.call('HGET', devices, device)
+	if old and old ~= id and redis.call('ZSCORE', index, old) then
+		revoke(old)
+		table.insert(revoked, old)
+	end
+end
+
+if max > 0 then
+	local over = redis.call('ZCARD', index) - max + 1
+	if over > 0 then
+		for _, old in ipairs(redis.call('ZRANGE', index, 0, over - 1)) do
+			revoke(old)
+			table.insert(revoked, old)
+		end
+	end
+end
+
+redis.call('SET', prefix .. 's:' .. id, ARGV[4], 'PX', math.max(1, expiresAt - now))
+redis.call('ZADD', index, expiresAt, id)
+if device ~= '' then
+	redis.call('HSET', devices, device, id)
+end
+expire()
+
+return revoked
+`)
+
+// refresh ARGV[3] session id, ARGV[4] session data, ARGV[5] expiry in unix millisecond.
+// returns 0 when the session does not exist
+var refresh = rdc.NewScript(scriptRevoke + `
+local id = ARGV[3]
+local expiresAt = tonumber(ARGV[5])
+
+if not redis.call('ZSCORE', index, id) or redis.call('EXISTS', prefix .. 's:' .. id) == 0 then
+	return 0
+end
+
+redis.call('SET', prefix .. 's:' .. id, ARGV[4], 'PX', math.max(1, expiresAt - now))
+redis.call('ZADD', index, expiresAt, id)
+expire()
+
+return 1
+`)
+
+// revoke ARGV[3...] session ids, empty ids revoke all sessions except ARGV[3] when ARGV[4] is "except".
+// returns the revoked session ids
+var revoke = rdc.NewScript(scriptRevoke + `
+local ids = {}
+if ARGV[4] == 'except' then
+	for _, id in ipairs(redis.call('ZRANGE', index, 0, -1)) do
+		if id ~= ARGV[3] then table.insert(ids, id) end
+	end
+else
+	for i = 3, #ARGV do table.insert(ids, ARGV[i]) end
+end
+
+local revoked = {}
+for _, id in ipairs(ids) do
+	if redis.call('ZSCORE', index, id) then
+		revoke(id)
+		table.insert(revoked, id)
+	end
+end
+expire()
+
+return revoked
+`)
@@ -0,0 +1,346 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/mqdvi-dp/go-common/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound returned when the session does not exist or expired
+	ErrNotFound = errors.New("session: not found")
+	// ErrRevoked returned when the session is revoked
+	ErrRevoked = errors.New("session: revoked")
+)
+
+// Session of the user on a device, the id is the jti of the token
+type Session struct {
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	DeviceId    string    `json:"device_id"`
+	DeviceInfo  string    `json:"device_info,omitempty"`
+	AppVersion  string    `json:"app_version,omitempty"`
+	Channel     string    `json:"channel,omitempty"`
+	IpAddress   string    `json:"ip_address,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Store is the sessions of the users on top of redis, the keys of the user are:
+//   - {prefix}:{user id}:sessions sorted set of the session ids, the score is the expiry
+//   - {prefix}:{user id}:devices hash of the device id and the session id
+//   - {prefix}:{user id}:s:{session id} data of the session
+//   - {prefix}:{user id}:r:{session id} revoked session until the session is expired
+type Store struct {
+	rdc rdc.Rdc
+	opt option
+}
+
+// New create new session store with redis client
+func New(client rdc.Rdc, opts ...OptionFunc) *Store {
+	s := &Store{rdc: client, opt: getDefaultOption()}
+	for _, opt := range opts {
+		opt(&s.opt)
+	}
+
+	return s
+}
+
+// userPrefix returns the key prefix of the user, the user id is the hashtag so the keys are in the same slot
+func (s *Store) userPrefix(userId string) string {
+	return fmt.Sprintf("%s:{%s}:", s.opt.prefix, userId)
+}
+
+// keys returns the sessions and the devices key of the user
+func (s *Store) keys(userId string) []string {
+	prefix := s.userPrefix(userId)
+	return []string{prefix + "sessions", prefix + "devices"}
+}
+
+// Create store new session, the id is generated when empty. the session of the same device
+// and the oldest sessions over the maximum concurrent sessions are revoked, returns the revoked session ids
+func (s *Store) Create(ctx context.Context, session Session) (Session, []string, error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Session:Create")
+	defer trace.Finish()
+
+	if session.UserId == "" {
+		err := fmt.Errorf("session: user id cannot be empty")
+		trace.SetError(err)
+		return Session{}, nil, err
+	}
+	if session.Id == "" {
+		session.Id = uuid.NewString()
+	}
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.RefreshedAt = now
+	session.ExpiresAt = now.Add(s.opt.ttl)
+
+	trace.SetTag("user_id", session.UserId)
+	trace.SetTag("session_id", session.Id)
+	trace.SetTag("device_id", session.DeviceId)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		trace.SetError(err)
+		return Session{}, nil, err
+	}
+
+	result, err := s.rdc.EvalScript(
+		ctx, create, s.keys(session.UserId),
+		s.userPrefix(session.UserId), now.UnixMilli(),
+		session.Id, string(data), session.ExpiresAt.UnixMilli(), s.opt.maxSessions, session.DeviceId,
+	)
+	if err != nil {
+		trace.SetError(err)
+		return Session{}, nil, err
+	}
+
+	revoked := toStrings(result)
+	trace.Log("revoked", revoked)
+
+	return session, revoked, nil
+}
+
+// Refresh extend the expiry of the session, returns ErrNotFound when the session does not exist
+func (s *Store) Refresh(ctx context.Context, userId, id string) (Session, error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Session:Refresh")
+	defer trace.Finish()
+
+	trace.SetTag("user_id", userId)
+	trace.SetTag("session_id", id)
+
+	session, err := s.Get(ctx, userId, id)
+	if err != nil {
+		trace.SetError(err)
+		return Session{}, err
+	}
+
+	now := time.Now()
+	session.RefreshedAt = now
+	session.ExpiresAt = now.Add(s.opt.ttl)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		trace.SetError(err)
+		return Session{}, err
+	}
+
+	result, err := s.rdc.EvalScript(
+		ctx, refresh, s.keys(userId),
+		s.userPrefix(userId), now.UnixMilli(),
+		id, string(data), session.ExpiresAt.UnixMilli(),
+	)
+	if err != nil {
+		trace.SetError(err)
+		return Session{}, err
+	}
+
+	// the session is revoked or expired after get
+	if n, _ := result.(int64); n == 0 {
+		trace.SetError(ErrNotFound)
+		return Session{}, ErrNotFound
+	}
+
+	return session, nil
+}
+
+// Get returns the session, returns ErrNotFound when the session does not exist
+func (s *Store) Get(ctx context.Context, userId, id string) (Session, error) {
+	var session Session
+
+	data, err := s.rdc.Get(ctx, s.userPrefix(userId)+"s:"+id)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return session, ErrNotFound
+		}
+		return session, err
+	}
+
+	err = json.Unmarshal([]byte(data), &session)
+	return session, err
+}
+
+// List returns the active sessions of the user, ordered by the expiry
+func (s *Store) List(ctx context.Context, userId string) ([]Session, error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Session:List")
+	defer trace.Finish()
+
+	trace.SetTag("user_id", userId)
+
+	prefix := s.userPrefix(userId)
+	ids, err := s.rdc.ZRangeByScore(ctx, s.keys(userId)[0], fmt.Sprintf("%d", time.Now().UnixMilli()), "+inf", 0, 0)
+	if err != nil {
+		trace.SetError(err)
+		return nil, err
+	}
+	if len(ids) < 1 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, prefix+"s:"+id)
+	}
+
+	values, err := s.rdc.MGet(ctx, keys...)
+	if err != nil {
+		trace.SetError(err)
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(values))
+	for _, key := range keys {
+		data, ok := values[key]
+		if !ok {
+			continue
+		}
+
+		var session Session
+		if err = json.Unmarshal([]byte(data), &session); err != nil {
+			trace.SetError(err)
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	trace.Log("result", len(sessions))
+
+	return sessions, nil
+}
+
+// Devices returns the device ids of the active sessions of the user
+func (s *Store) Devices(ctx context.Context, userId string) ([]string, error) {
+	sessions, err := s.List(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.DeviceId != "" {
+			devices = append(devices, session.DeviceId)
+		}
+	}
+
+	return devices, nil
+}
+
+// Revoke revoke the sessions of the user, returns the revoked session ids
+func (s *Store) Revoke(ctx context.Context, userId string, ids ...string) ([]string, error) {
+	if len(ids) < 1 {
+		return nil, nil
+	}
+
+	return s.revoke(ctx, "Session:Revoke", userId, ids...)
+}
+
+// RevokeAll revoke all sessions of the user except the session id (e.g.: log out the other devices),
+// empty except revoke all sessions. returns the revoked session ids
+func (s *Store) RevokeAll(ctx context.Context, userId, except string) ([]string, error) {
+	return s.revoke(ctx, "Session:RevokeAll", userId, except, "except")
+}
+
+// RevokeDevice revoke the session of the device, returns the revoked session ids
+func (s *Store) RevokeDevice(ctx context.Context, userId, deviceId string) ([]string, error) {
+	id, err := s.rdc.HGet(ctx, s.keys(userId)[1], deviceId)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return s.Revoke(ctx, userId, id)
+}
+
+func (s *Store) revoke(ctx context.Context, operation, userId string, args ...string) ([]string, error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, operation)
+	defer trace.Finish()
+
+	trace.SetTag("user_id", userId)
+	trace.Log("arguments", args)
+
+	argv := []interface{}{s.userPrefix(userId), time.Now().UnixMilli()}
+	for _, arg := range args {
+		argv = append(argv, arg)
+	}
+
+	result, err := s.rdc.EvalScript(ctx, revoke, s.keys(userId), argv...)
+	if err != nil {
+		trace.SetError(err)
+		return nil, err
+	}
+
+	revoked := toStrings(result)
+	trace.Log("revoked", revoked)
+
+	return revoked, nil
+}
+
+// IsRevoked checks the session id (jti) is revoked, on strict the session which does not exist is revoked too.
+// the empty id (the token without jti) is revoked on strict, otherwise it is not checked
+func (s *Store) IsRevoked(ctx context.Context, userId, id string) (bool, error) {
+	trace, ctx := tracer.StartTraceWithContext(ctx, "Session:IsRevoked")
+	defer trace.Finish()
+
+	trace.SetTag("user_id", userId)
+	trace.SetTag("session_id", id)
+
+	if id == "" {
+		trace.SetTag("revoked", s.opt.strict)
+		return s.opt.strict, nil
+	}
+
+	prefix := s.userPrefix(userId)
+	revokedKey, sessionKey := prefix+"r:"+id, prefix+"s:"+id
+
+	values, err := s.rdc.MGet(ctx, revokedKey, sessionKey)
+	if err != nil {
+		trace.SetError(err)
+		return false, err
+	}
+
+	_, revoked := values[revokedKey]
+	if _, ok := values[sessionKey]; !ok && s.opt.strict {
+		revoked = true
+	}
+
+	trace.SetTag("revoked", revoked)
+
+	return revoked, nil
+}
+
+// Validate returns ErrRevoked when the session id (jti) is revoked
+func (s *Store) Validate(ctx context.Context, userId, id string) error {
+	revoked, err := s.IsRevoked(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrRevoked
+	}
+
+	return nil
+}
+
+func toStrings(result interface{}) []string {
+	values, _ := result.([]interface{})
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+
+	return strs
+}
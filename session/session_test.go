@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/mqdvi-dp/go-common/config/database/rdc"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T, opts ...OptionFunc) (*Store, *miniredis.Miniredis) {
+	m := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	opts = append([]OptionFunc{SetPrefix("session"), SetTTL(time.Hour), SetMaxSessions(5), SetStrict(false)}, opts...)
+	return New(rdc.New(client), opts...), m
+}
+
+func TestCreateReplaceDevice(t *testing.T) {
+	s, m := newTestStore(t)
+	ctx := context.Background()
+
+	_, revoked, err := s.Create(ctx, Session{Id: "s1", UserId: "u1", DeviceId: "d1"})
+	assert.NoError(t, err)
+	assert.Empty(t, revoked)
+
+	// the new session of the same device revokes the old one
+	_, revoked, err = s.Create(ctx, Session{Id: "s2", UserId: "u1", DeviceId: "d1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, revoked)
+
+	assert.False(t, m.Exists("session:{u1}:s:s1"))
+	assert.ErrorIs(t, s.Validate(ctx, "u1", "s1"), ErrRevoked)
+	assert.NoError(t, s.Validate(ctx, "u1", "s2"))
+
+	devices, err := s.Devices(ctx, "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d1"}, devices)
+
+	// the revoked marker lives until the session would have expired
+	ttl := m.TTL("session:{u1}:r:s1")
+	assert.Greater(t, ttl, 59*time.Minute)
+	assert.LessOrEqual(t, ttl, time.Hour)
+}
+
+func TestCreateEvictOldest(t *testing.T) {
+	s, _ := newTestStore(t, SetMaxSessions(2))
+	ctx := context.Background()
+
+	for _, id := range []string{"s1", "s2"} {
+		_, revoked, err := s.Create(ctx, Session{Id: id, UserId: "u1", DeviceId: "device-" + id})
+		assert.NoError(t, err)
+		assert.Empty(t, revoked)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// the oldest session over the maximum is revoked
+	_, revoked, err := s.Create(ctx, Session{Id: "s3", UserId: "u1", DeviceId: "device-s3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, revoked)
+
+	sessions, err := s.List(ctx, "u1")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "s2", sessions[0].Id)
+	assert.Equal(t, "s3", sessions[1].Id)
+}
+
+func TestRefresh(t *testing.T) {
+	s, m := newTestStore(t)
+	ctx := context.Background()
+
+	created, _, err := s.Create(ctx, Session{Id: "s1", UserId: "u1"})
+	assert.NoError(t, err)
+
+	time.Sleep(2 * time.Millisecond)
+	refreshed, err := s.Refresh(ctx, "u1", "s1")
+	assert.NoError(t, err)
+	assert.True(t, refreshed.ExpiresAt.After(created.ExpiresAt))
+	assert.Greater(t, m.TTL("session:{u1}:sessions"), 59*time.Minute)
+
+	// the revoked session cannot be refreshed
+	_, err = s.Revoke(ctx, "u1", "s1")
+	assert.NoError(t, err)
+	_, err = s.Refresh(ctx, "u1", "s1")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRevokeAll(t *testing.T) {
+	s, m := newTestStore(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"s1", "s2", "s3"} {
+		_, _, err := s.Create(ctx, Session{Id: id, UserId: "u1", DeviceId: "device-" + id})
+		assert.NoError(t, err)
+	}
+
+	revoked, err := s.RevokeAll(ctx, "u1", "s2")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"s1", "s3"}, revoked)
+	devices, err := m.HKeys("session:{u1}:devices")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"device-s2"}, devices)
+
+	// revoking the last session removes the keys of the user
+	revoked, err = s.RevokeDevice(ctx, "u1", "device-s2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s2"}, revoked)
+	assert.False(t, m.Exists("session:{u1}:sessions"))
+	assert.False(t, m.Exists("session:{u1}:devices"))
+}
+
+func TestIsRevoked(t *testing.T) {
+	ctx := context.Background()
+
+	s, _ := newTestStore(t)
+	revoked, err := s.IsRevoked(ctx, "u1", "unknown")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	revoked, err = s.IsRevoked(ctx, "u1", "")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// on strict the session which does not exist and the token without jti are revoked
+	s, _ = newTestStore(t, SetStrict(true))
+	revoked, err = s.IsRevoked(ctx, "u1", "unknown")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = s.IsRevoked(ctx, "u1", "")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
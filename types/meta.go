@@ -33,3 +33,10 @@ type Meta struct {
 	CurrentPage int64 `json:"current_page,omitempty"`
 	PrevPage    int64 `json:"prev_page,omitempty"`
 }
+
+// CursorMeta metadata response of keyset (cursor) pagination
+type CursorMeta struct {
+	PerPage    int64  `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+}
@@ -27,6 +27,8 @@ type TokenClaim struct {
 	ClientKey           string    `json:"client_key"`
 	ClientSecret        string    `json:"client_secret"`
 	PublicKey           string    `json:"public_key"`
+	// Jti is the session id of the token, used by the revocation lookup
+	Jti string `json:"jti"`
 	// identity user
 	State                 string `json:"state"`
 	City                  string `json:"city"`
@@ -65,6 +67,7 @@ func (tc TokenClaim) GetClientName() string             { return tc.ClientName }
 func (tc TokenClaim) GetClientKey() string              { return tc.ClientKey }
 func (tc TokenClaim) GetClientSecret() string           { return tc.ClientSecret }
 func (tc TokenClaim) GetPublicKey() string              { return tc.PublicKey }
+func (tc TokenClaim) GetJti() string                    { return tc.Jti }
 func (tc TokenClaim) GetState() string                  { return tc.State }
 func (tc TokenClaim) GetCity() string                   { return tc.City }
 func (tc TokenClaim) GetNik() string                    { return tc.Nik }